@@ -9,6 +9,7 @@ import (
 	"os"
 	"runtime/debug"
 	"text/template"
+	"time"
 
 	"github.com/glugox/unogo/db"
 	"github.com/glugox/unogo/db/migration"
@@ -34,6 +35,8 @@ var (
 	sslcert      = flags.String("ssl-cert", "", "file path to SSL certificates in pem format (only support on mysql)")
 	sslkey       = flags.String("ssl-key", "", "file path to SSL key in pem format (only support on mysql)")
 	noVersioning = flags.Bool("no-versioning", false, "apply migration commands with no versioning, in file order, from directory pointed to")
+	dryRun       = flags.Bool("dry-run", false, "print the migrations that would run, with their statements, without touching the database")
+	lockTimeout  = flags.Duration("lock-timeout", time.Minute, "how long to wait for another runner to release the migration lock")
 )
 var (
 	migrationVersion = ""
@@ -123,6 +126,10 @@ func main() {
 	if *noVersioning {
 		options = append(options, migration.WithNoVersioning())
 	}
+	if *dryRun {
+		options = append(options, migration.WithDryRun())
+	}
+	options = append(options, migration.WithLockTimeout(*lockTimeout))
 	if err := migration.RunWithOptions(
 		command,
 		db,
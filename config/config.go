@@ -6,7 +6,7 @@ var App *AppConfig
 var Route *RouteConfig
 var View *ViewConfig
 
-//var Database *DatabaseConfig
+var Database *DatabaseConfig
 var Cookie *CookieConfig
 var Session *SessionConfig
 
@@ -14,6 +14,7 @@ func init() {
 	loadAppConfig()
 	loadViewConfig()
 	loadRouteConfig()
+	loadDatabaseConfig()
 	loadCookieConfig()
 	loadSessionConfig()
 }
@@ -35,6 +36,26 @@ func loadRouteConfig() {
 	}
 }
 
+func loadDatabaseConfig() {
+	Database = &DatabaseConfig{
+		Connection: "mysql",
+		Connections: map[string]Connection{
+			"mysql": {
+				Driver:   "mysql",
+				Host:     "127.0.0.1",
+				Port:     "3306",
+				Database: "unogo",
+				Username: "root",
+				Password: "root",
+				Charset:  "utf8",
+			},
+		},
+		MaxOpenConns:    0,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: time.Hour,
+	}
+}
+
 func loadViewConfig() {
 	View = &ViewConfig{
 		Path: "view",
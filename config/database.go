@@ -1,8 +1,16 @@
 package config
 
+import "time"
+
 type DatabaseConfig struct {
+	//Default Connection Name
 	Connection  string
 	Connections map[string]Connection
+
+	//Connection Pool Settings, applied to every connection
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
 type Connection struct {
@@ -15,4 +23,13 @@ type Connection struct {
 	Charset  string
 	Prefix   string
 	Engine   string
+
+	//Postgres SSL Mode, such as "disable" or "require"; libpq's "prefer" when empty
+	SslMode string
+
+	//Read Replica Hosts, the other settings are shared with Host
+	Read []string
+
+	//Read From Host After Writing
+	Sticky bool
 }
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/glugox/unogo/orm"
 )
 
 //Request HTTP request
@@ -19,6 +21,7 @@ type Request struct {
 	files         map[string]*File
 	session       Session
 	CookieHandler *Cookie
+	DB            *orm.Connection
 }
 
 // NewRequest create a new HTTP request from *http.Request
@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSetBaseFS(t *testing.T) {
+	if err := SetDialect("sqlite3"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetDialect("postgres") })
+
+	SetBaseFS(fstest.MapFS{
+		"migrations/00001_create_users.sql": {Data: []byte("-- +goose Up\nCREATE TABLE users (id INTEGER PRIMARY KEY);\n-- +goose Down\nDROP TABLE users;\n")},
+		"migrations/00002_create_posts.sql": {Data: []byte("-- +goose Up\nCREATE TABLE posts (id INTEGER PRIMARY KEY);\n-- +goose Down\nDROP TABLE posts;\n")},
+	})
+	t.Cleanup(func() { SetBaseFS(nil) })
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := Up(db, "./migrations"); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"users", "posts"} {
+		if !tableExists(t, db, table) {
+			t.Errorf("invalid up from base FS: table %s not created", table)
+		}
+	}
+
+	if err := Down(db, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	if tableExists(t, db, "posts") {
+		t.Errorf("invalid down from base FS: table posts not dropped")
+	}
+}
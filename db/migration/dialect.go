@@ -1,8 +1,12 @@
 package migration
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"time"
 )
 
 // SQLDialect abstracts the details of specific SQL dialects
@@ -12,16 +16,26 @@ type SQLDialect interface {
 	insertVersionSQL() string      // sql string to insert the initial version table row
 	deleteVersionSQL() string      // sql string to delete version
 	migrationSQL() string          // sql string to retrieve migrations
+	versionTableExistsSQL() string // sql string counting the version tables named by its parameter
 	dbVersionQuery(db *sql.DB) (*sql.Rows, error)
+	lock(db *sql.DB, timeout time.Duration) (func() error, error) // take the migration lock, returning its release
 }
 
-var dialect SQLDialect = &PostgresDialect{}
+var (
+	dialect     SQLDialect = &PostgresDialect{}
+	dialectName            = "postgres"
+)
 
 // GetDialect gets the SQLDialect
 func GetDialect() SQLDialect {
 	return dialect
 }
 
+// GetDialectName gets the name the SQLDialect was set by
+func GetDialectName() string {
+	return dialectName
+}
+
 // SetDialect sets the SQLDialect
 func SetDialect(d string) error {
 	switch d {
@@ -42,6 +56,7 @@ func SetDialect(d string) error {
 	default:
 		return fmt.Errorf("%q: unknown dialect", d)
 	}
+	dialectName = d
 
 	return nil
 }
@@ -82,6 +97,25 @@ func (pg PostgresDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=$1;", TableName())
 }
 
+func (pg PostgresDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_name = $1"
+}
+
+// lock takes a session advisory lock, polling as pg_advisory_lock has no
+// timeout.
+func (pg PostgresDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return sessionLock(db, func(conn *sql.Conn) error {
+		return pollLock(timeout, func() (bool, error) {
+			var locked bool
+			err := conn.QueryRowContext(context.Background(), "SELECT pg_try_advisory_lock($1)", lockKey()).Scan(&locked)
+			return locked, err
+		})
+	}, func(conn *sql.Conn) error {
+		var unlocked bool
+		return conn.QueryRowContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey()).Scan(&unlocked)
+	})
+}
+
 // MySQL
 
 // MySQLDialect struct.
@@ -118,6 +152,35 @@ func (m MySQLDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=?;", TableName())
 }
 
+func (m MySQLDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+}
+
+func (m MySQLDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return mysqlLock(db, timeout)
+}
+
+// mysqlLock takes a named lock of the current database with GET_LOCK, which
+// waits for the timeout itself.
+func mysqlLock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	const name = "CONCAT(COALESCE(DATABASE(), ''), '.', ?)"
+
+	return sessionLock(db, func(conn *sql.Conn) error {
+		var locked sql.NullInt64
+		seconds := int(math.Ceil(timeout.Seconds()))
+		if err := conn.QueryRowContext(context.Background(), "SELECT GET_LOCK("+name+", ?)", lockName(), seconds).Scan(&locked); err != nil {
+			return fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		if !locked.Valid || locked.Int64 != 1 {
+			return lockTimeoutError(timeout)
+		}
+		return nil
+	}, func(conn *sql.Conn) error {
+		var released sql.NullInt64
+		return conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK("+name+")", lockName()).Scan(&released)
+	})
+}
+
 // MSSQL
 
 // SqlServerDialect struct.
@@ -166,6 +229,15 @@ func (m SqlServerDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=@p1;", TableName())
 }
 
+func (m SqlServerDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_NAME = @p1"
+}
+
+// lock does not lock, concurrent runners are not guarded against.
+func (m SqlServerDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return noLock()
+}
+
 // Sqlite3
 
 // Sqlite3Dialect struct.
@@ -201,6 +273,38 @@ func (m Sqlite3Dialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=?;", TableName())
 }
 
+func (m Sqlite3Dialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+}
+
+// lock takes the single row of a lock table, as SQLite has no advisory
+// locks. The row of a crashed runner has to be deleted by hand.
+func (m Sqlite3Dialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY, tstamp TIMESTAMP DEFAULT (datetime('now')))", lockName())); err != nil {
+		return nil, fmt.Errorf("failed to create the migration lock table: %w", err)
+	}
+
+	err := pollLock(timeout, func() (bool, error) {
+		result, err := db.Exec(fmt.Sprintf("INSERT OR IGNORE INTO %s (id) VALUES (1)", lockName()))
+		if err != nil {
+			return false, err
+		}
+		inserted, err := result.RowsAffected()
+		return inserted == 1, err
+	})
+	if errors.Is(err, ErrLockTimeout) {
+		return nil, fmt.Errorf("%w, delete the row of %s if no migration is running", err, lockName())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = 1", lockName()))
+		return err
+	}, nil
+}
+
 // Redshift
 
 // RedshiftDialect struct.
@@ -237,6 +341,15 @@ func (rs RedshiftDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=$1;", TableName())
 }
 
+func (rs RedshiftDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_name = $1"
+}
+
+// lock does not lock, Redshift has no advisory locks.
+func (rs RedshiftDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return noLock()
+}
+
 // TiDB
 
 // TiDBDialect struct.
@@ -273,6 +386,14 @@ func (m TiDBDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("DELETE FROM %s WHERE version_id=?;", TableName())
 }
 
+func (m TiDBDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+}
+
+func (m TiDBDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return mysqlLock(db, timeout)
+}
+
 // ClickHouse
 
 // ClickHouseDialect struct.
@@ -306,3 +427,12 @@ func (m ClickHouseDialect) migrationSQL() string {
 func (m ClickHouseDialect) deleteVersionSQL() string {
 	return fmt.Sprintf("ALTER TABLE %s DELETE WHERE version_id = $1", TableName())
 }
+
+func (m ClickHouseDialect) versionTableExistsSQL() string {
+	return "SELECT COUNT(*) FROM system.tables WHERE database = currentDatabase() AND name = $1"
+}
+
+// lock does not lock, ClickHouse has no advisory locks.
+func (m ClickHouseDialect) lock(db *sql.DB, timeout time.Duration) (func() error, error) {
+	return noLock()
+}
@@ -13,10 +13,27 @@ func Down(db *sql.DB, dir string, opts ...OptionsFunc) error {
 	for _, f := range opts {
 		f(option)
 	}
+	return withLock(db, option, func() error {
+		return down(db, dir, option)
+	})
+}
+
+// down rolls back a single migration while holding the migration lock.
+func down(db *sql.DB, dir string, option *options) error {
 	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
 	if err != nil {
 		return err
 	}
+	if option.dryRun {
+		applied, err := appliedMigrations(db, migrations, minVersion, option)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 1 {
+			applied = applied[:1]
+		}
+		return dryRun(applied, false)
+	}
 	if option.noVersioning {
 		if len(migrations) == 0 {
 			return nil
@@ -42,10 +59,24 @@ func DownTo(db *sql.DB, dir string, version int64, opts ...OptionsFunc) error {
 	for _, f := range opts {
 		f(option)
 	}
+	return withLock(db, option, func() error {
+		return downTo(db, dir, version, option)
+	})
+}
+
+// downTo rolls back migrations to a specific version while holding the migration lock.
+func downTo(db *sql.DB, dir string, version int64, option *options) error {
 	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
 	if err != nil {
 		return err
 	}
+	if option.dryRun {
+		applied, err := appliedMigrations(db, migrations, version, option)
+		if err != nil {
+			return err
+		}
+		return dryRun(applied, false)
+	}
 	if option.noVersioning {
 		return downToNoVersioning(db, migrations, version)
 	}
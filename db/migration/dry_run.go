@@ -0,0 +1,97 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"github.com/glugox/unogo/log"
+)
+
+// dryRun prints the migrations, in order, with the statements they would
+// run in the given direction.
+func dryRun(migrations Migrations, direction bool) error {
+	name := "down"
+	if direction {
+		name = "up"
+	}
+
+	if len(migrations) == 0 {
+		log.Info("migration: dry run, no migrations to run")
+		return nil
+	}
+
+	for _, m := range migrations {
+		statements, err := m.Statements(direction)
+		if err != nil {
+			return err
+		}
+
+		log.Info("DRY RUN %-4s %v", name, filepath.Base(m.Source))
+		if filepath.Ext(m.Source) == ".go" {
+			if !m.Registered {
+				return fmt.Errorf("ERROR %v: failed to run Go migration: Go functions must be registered and built into a custom binary", m.Source)
+			}
+			if fn := m.funcName(direction); fn != "" {
+				log.Info("func %s", fn)
+			}
+		}
+		for _, statement := range statements {
+			log.Info("%s", clearStatement(statement))
+		}
+	}
+
+	return nil
+}
+
+// pendingMigrations returns the migrations up to version which are not
+// applied, in order. The version table is read but never created.
+func pendingMigrations(db *sql.DB, migrations Migrations, version int64, option *options) (Migrations, error) {
+	statuses, err := migrationStatuses(db, option)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending Migrations
+	for _, m := range migrations {
+		if m.Version > version || statuses[m.Version] {
+			continue
+		}
+		pending = append(pending, m)
+		if option.applyUpByOne {
+			break
+		}
+	}
+
+	return pending, nil
+}
+
+// appliedMigrations returns the applied migrations above version, latest
+// first. The version table is read but never created.
+func appliedMigrations(db *sql.DB, migrations Migrations, version int64, option *options) (Migrations, error) {
+	statuses, err := migrationStatuses(db, option)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied Migrations
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].Version <= version {
+			break
+		}
+		if option.noVersioning || statuses[migrations[i].Version] {
+			applied = append(applied, migrations[i])
+		}
+	}
+
+	return applied, nil
+}
+
+// migrationStatuses returns whether each version is applied, none of them
+// without versioning.
+func migrationStatuses(db *sql.DB, option *options) (map[int64]bool, error) {
+	if option.noVersioning {
+		return map[int64]bool{}, nil
+	}
+	return dbMigrationsStatus(db)
+}
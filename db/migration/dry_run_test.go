@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	db, dir := newTestMigrations(t, "users", "posts")
+
+	if err := Up(db, dir, WithDryRun()); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"users", "posts", TableName()} {
+		if tableExists(t, db, table) {
+			t.Errorf("invalid dry run: table %s created", table)
+		}
+	}
+
+	if err := UpTo(db, dir, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	option := &options{dryRun: true}
+
+	pending, err := pendingMigrations(db, migrations, maxVersion, option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Version != 2 {
+		t.Fatalf("invalid pending migrations: got:%v", pending)
+	}
+
+	statements, err := pending[0].Statements(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 1 || clearStatement(statements[0]) != "CREATE TABLE posts (id INTEGER PRIMARY KEY);\n" {
+		t.Errorf("invalid statements: got:%q", statements)
+	}
+
+	for _, run := range []func() error{
+		func() error { return Up(db, dir, WithDryRun()) },
+		func() error { return Down(db, dir, WithDryRun()) },
+		func() error { return Redo(db, dir, WithDryRun()) },
+		func() error { return Reset(db, dir, WithDryRun()) },
+	} {
+		if err := run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !tableExists(t, db, "users") || tableExists(t, db, "posts") {
+		t.Errorf("invalid dry run: tables changed")
+	}
+	if version, err := GetDBVersion(db); err != nil || version != 1 {
+		t.Errorf("invalid version after dry run: got:%d,%v want:%d", version, err, 1)
+	}
+}
@@ -0,0 +1,56 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glugox/unogo/log"
+	"github.com/glugox/unogo/support/os/filesystem"
+)
+
+// Fix renames the timestamped migration files of dir into sequential
+// versions, following the last sequential one.
+func Fix(dir string) error {
+	// always use OsFS here because it's modifying operation
+	migrations, err := collectMigrationsFS(filesystem.OsFS{}, dir, minVersion, maxVersion)
+	if err != nil {
+		return err
+	}
+
+	// split into timestamped and versioned migrations
+	tsMigrations, err := migrations.timestamped()
+	if err != nil {
+		return err
+	}
+
+	vMigrations, err := migrations.versioned()
+	if err != nil {
+		return err
+	}
+
+	// Initial version.
+	version := int64(1)
+	if last, err := vMigrations.Last(); err == nil {
+		version = last.Version + 1
+	}
+
+	// fix filenames by replacing timestamps with sequential versions
+	for _, tsm := range tsMigrations {
+		oldPath := tsm.Source
+		newPath := filepath.Join(
+			filepath.Dir(oldPath),
+			strings.Replace(filepath.Base(oldPath), fmt.Sprintf("%d", tsm.Version), fmt.Sprintf(verTplSeq, version), 1),
+		)
+
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return err
+		}
+
+		log.Info("RENAMED %s => %s", filepath.Base(oldPath), filepath.Base(newPath))
+		version++
+	}
+
+	return nil
+}
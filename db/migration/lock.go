@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	// ErrLockTimeout when the migration lock is held by another runner for
+	// longer than the lock timeout.
+	ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+	defaultLockTimeout = time.Minute
+	lockRetryInterval  = 100 * time.Millisecond
+)
+
+// withLock runs fn while holding the migration lock, so that concurrent
+// runners apply every migration once. Dry runs do not lock.
+func withLock(db *sql.DB, option *options, fn func() error) error {
+	if option.dryRun {
+		return fn()
+	}
+
+	timeout := option.lockTimeout
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+
+	unlock, err := GetDialect().lock(db, timeout)
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	if uerr := unlock(); err == nil && uerr != nil {
+		err = fmt.Errorf("failed to release the migration lock: %w", uerr)
+	}
+	return err
+}
+
+// pollLock tries to take a lock until it is taken or the timeout passes.
+func pollLock(timeout time.Duration, try func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		locked, err := try()
+		if err != nil {
+			return fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return lockTimeoutError(timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func lockTimeoutError(timeout time.Duration) error {
+	return fmt.Errorf("%w: another runner has held the lock of %s for over %v", ErrLockTimeout, TableName(), timeout)
+}
+
+// lockName returns the name of the migration lock, after the version table.
+func lockName() string {
+	return TableName() + "_lock"
+}
+
+// lockKey returns the migration lock as a number, for the databases with
+// numbered locks.
+func lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(lockName()))
+	return int64(h.Sum64())
+}
+
+// sessionLock holds a lock owned by a database session on a dedicated
+// connection of the pool, released with the returned function.
+func sessionLock(db *sql.DB, lock func(conn *sql.Conn) error, unlock func(conn *sql.Conn) error) (func() error, error) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lock(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		err := unlock(conn)
+		if cerr := conn.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
+
+// noLock is the lock of the databases without advisory locks.
+func noLock() (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	db, dir := newTestMigrations(t, "users")
+
+	unlock, err := GetDialect().lock(db, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Up(db, dir, WithLockTimeout(200*time.Millisecond))
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("invalid up while locked: got:%v want:%v", err, ErrLockTimeout)
+	}
+	if tableExists(t, db, "users") {
+		t.Errorf("invalid up while locked: table users created")
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Up(db, dir, WithLockTimeout(200*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Reset(db, dir, WithNoVersioning(), WithLockTimeout(200*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + lockName()).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("invalid lock rows after release: got:%d want:%d", count, 0)
+	}
+}
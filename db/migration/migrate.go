@@ -141,6 +141,9 @@ func AddNamedMigration(filename string, up func(*sql.Tx) error, down func(*sql.T
 }
 
 func collectMigrationsFS(fsys fs.FS, dirpath string, current, target int64) (Migrations, error) {
+	// fs.FS paths have no "./" prefix, as in the default "./migrations".
+	dirpath = path.Clean(dirpath)
+
 	if _, err := fs.Stat(fsys, dirpath); errors.Is(err, fs.ErrNotExist) {
 		return nil, fmt.Errorf("%s directory does not exist", dirpath)
 	}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -18,7 +20,7 @@ import (
 var (
 	minVersion             = int64(0)
 	maxVersion             = int64((1 << 63) - 1)
-	timestampFormat        = "20220703000000"
+	timestampFormat        = "20060102150405"
 	verbose                = true
 	verTplSeq              = "%05v"
 	matchSQLComments       = regexp.MustCompile(`(?m)^--.*$[\r\n]*`)
@@ -61,6 +63,24 @@ func SetTableName(n string) {
 	tableName = n
 }
 
+// SetBaseFS sets the FS migrations are read from, so that they can be
+// embedded in the binary:
+//
+//	//go:embed migrations/*.sql
+//	var embedMigrations embed.FS
+//
+//	migration.SetBaseFS(embedMigrations)
+//	migration.Up(db, "migrations")
+//
+// Calling it with nil reads them from the os filesystem again. Create and
+// Fix always use the os filesystem, as they write files.
+func SetBaseFS(fsys fs.FS) {
+	if fsys == nil {
+		fsys = filesystem.OsFS{}
+	}
+	baseFS = fsys
+}
+
 func (m *Migration) String() string {
 	return fmt.Sprintf(m.Source)
 }
@@ -81,6 +101,39 @@ func (m *Migration) Down(db *sql.DB) error {
 	return nil
 }
 
+// Statements returns the statements of a SQL migration in the given
+// direction without running them. Go migrations have none.
+func (m *Migration) Statements(direction bool) ([]string, error) {
+	if filepath.Ext(m.Source) != ".sql" {
+		return nil, nil
+	}
+
+	f, err := baseFS.Open(m.Source)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR %v: failed to open SQL migration file: %w", filepath.Base(m.Source), err)
+	}
+	defer f.Close()
+
+	statements, _, err := parseSQLMigration(f, direction)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR %v: failed to parse SQL migration file: %w", filepath.Base(m.Source), err)
+	}
+	return statements, nil
+}
+
+// funcName returns the name of the function of a Go migration in the given
+// direction, empty if there is none.
+func (m *Migration) funcName(direction bool) string {
+	fn := m.UpFn
+	if !direction {
+		fn = m.DownFn
+	}
+	if fn == nil {
+		return ""
+	}
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+}
+
 func (m *Migration) run(db *sql.DB, direction bool) error {
 	switch filepath.Ext(m.Source) {
 	case ".sql":
@@ -267,25 +320,21 @@ func run(command string, db *sql.DB, dir string, args []string, options ...Optio
 			return err
 		}
 	case "fix":
-		return fmt.Errorf("fix is not implemented")
-		/*if err := Fix(dir); err != nil {
+		if err := Fix(dir); err != nil {
 			return err
-		}*/
+		}
 	case "redo":
-		/*if err := Redo(db, dir, options...); err != nil {
+		if err := Redo(db, dir, options...); err != nil {
 			return err
-		}*/
-		return fmt.Errorf("redo is not implemented")
+		}
 	case "reset":
-		/*if err := Reset(db, dir, options...); err != nil {
+		if err := Reset(db, dir, options...); err != nil {
 			return err
-		}*/
-		return fmt.Errorf("reset is not implemented")
+		}
 	case "status":
-		/*if err := Status(db, dir, options...); err != nil {
+		if err := Status(db, dir, options...); err != nil {
 			return err
-		}*/
-		return fmt.Errorf("status is not implemented")
+		}
 	case "version":
 		if err := Version(db, dir, options...); err != nil {
 			return err
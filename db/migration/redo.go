@@ -0,0 +1,64 @@
+package migration
+
+import (
+	"database/sql"
+)
+
+// Redo rolls back the most recently applied migration, then runs it again.
+func Redo(db *sql.DB, dir string, opts ...OptionsFunc) error {
+	option := &options{}
+	for _, f := range opts {
+		f(option)
+	}
+	return withLock(db, option, func() error {
+		return redo(db, dir, option)
+	})
+}
+
+// redo rolls back and re-applies the latest migration while holding the migration lock.
+func redo(db *sql.DB, dir string, option *options) error {
+	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
+	if err != nil {
+		return err
+	}
+
+	if option.dryRun {
+		applied, err := appliedMigrations(db, migrations, minVersion, option)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 1 {
+			applied = applied[:1]
+		}
+		if err := dryRun(applied, false); err != nil {
+			return err
+		}
+		return dryRun(applied, true)
+	}
+
+	var currentVersion int64
+	if option.noVersioning {
+		if len(migrations) == 0 {
+			return nil
+		}
+		currentVersion = migrations[len(migrations)-1].Version
+	} else {
+		if currentVersion, err = GetDBVersion(db); err != nil {
+			return err
+		}
+	}
+
+	current, err := migrations.Current(currentVersion)
+	if err != nil {
+		return err
+	}
+	current.noVersioning = option.noVersioning
+
+	if err := current.Down(db); err != nil {
+		return err
+	}
+	if err := current.Up(db); err != nil {
+		return err
+	}
+	return nil
+}
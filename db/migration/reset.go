@@ -0,0 +1,91 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// Reset rolls back all migrations
+func Reset(db *sql.DB, dir string, opts ...OptionsFunc) error {
+	option := &options{}
+	for _, f := range opts {
+		f(option)
+	}
+	return withLock(db, option, func() error {
+		return reset(db, dir, option)
+	})
+}
+
+// reset rolls back all migrations while holding the migration lock.
+func reset(db *sql.DB, dir string, option *options) error {
+	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
+	if err != nil {
+		return fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	if option.dryRun {
+		applied, err := appliedMigrations(db, migrations, minVersion, option)
+		if err != nil {
+			return err
+		}
+		return dryRun(applied, false)
+	}
+	if option.noVersioning {
+		return downTo(db, dir, minVersion, option)
+	}
+
+	statuses, err := dbMigrationsStatus(db)
+	if err != nil {
+		return fmt.Errorf("failed to get status of migrations: %w", err)
+	}
+	sort.Sort(sort.Reverse(migrations))
+
+	for _, migration := range migrations {
+		if !statuses[migration.Version] {
+			continue
+		}
+		if err = migration.Down(db); err != nil {
+			return fmt.Errorf("failed to db-down: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// dbMigrationsStatus gets whether each version in the version table is
+// applied, from its most recent record. None is applied while the version
+// table does not exist.
+func dbMigrationsStatus(db *sql.DB) (map[int64]bool, error) {
+	var tables int
+	if err := db.QueryRow(GetDialect().versionTableExistsSQL(), TableName()).Scan(&tables); err != nil {
+		return nil, fmt.Errorf("failed to look up the version table: %w", err)
+	}
+	if tables == 0 {
+		return map[int64]bool{}, nil
+	}
+
+	rows, err := GetDialect().dbVersionQuery(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the version table: %w", err)
+	}
+	defer rows.Close()
+
+	// The most recent record for each migration specifies
+	// whether it has been applied or rolled back.
+	result := make(map[int64]bool)
+
+	for rows.Next() {
+		var row MigrationRecord
+		if err = rows.Scan(&row.VersionID, &row.IsApplied); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if _, ok := result[row.VersionID]; ok {
+			continue
+		}
+
+		result[row.VersionID] = row.IsApplied
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,131 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/glugox/unogo/orm/driver/sqlite"
+)
+
+// newTestMigrations Open a fresh SQLite database and write SQL migrations
+// creating the given tables into a temporary directory.
+func newTestMigrations(t *testing.T, tables ...string) (*sql.DB, string) {
+	t.Helper()
+
+	if err := SetDialect("sqlite3"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetDialect("postgres") })
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "migrations.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for i, table := range tables {
+		content := "-- +goose Up\nCREATE TABLE " + table + " (id INTEGER PRIMARY KEY);\n" +
+			"-- +goose Down\nDROP TABLE " + table + ";\n"
+		name := filepath.Join(dir, fmt.Sprintf("%05d_create_%s.sql", i+1, table))
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db, dir
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestRedoAndReset(t *testing.T) {
+	db, dir := newTestMigrations(t, "users", "posts")
+
+	if err := Status(db, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Up(db, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Status(db, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("INSERT INTO posts (id) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Redo(db, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM posts").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("invalid posts after redo: got:%d want:%d", count, 0)
+	}
+	if version, err := GetDBVersion(db); err != nil || version != 2 {
+		t.Errorf("invalid version after redo: got:%d,%v want:%d", version, err, 2)
+	}
+
+	if err := Reset(db, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"users", "posts"} {
+		if tableExists(t, db, table) {
+			t.Errorf("invalid reset: table %s still exists", table)
+		}
+	}
+	if version, err := GetDBVersion(db); err != nil || version != 0 {
+		t.Errorf("invalid version after reset: got:%d,%v want:%d", version, err, 0)
+	}
+}
+
+func TestResetVersionTableError(t *testing.T) {
+	db, dir := newTestMigrations(t, "users")
+
+	// A version table without the expected columns cannot be queried.
+	if _, err := db.Exec("CREATE TABLE " + TableName() + " (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reset(db, dir); err == nil {
+		t.Errorf("invalid reset error: got:nil want:error")
+	}
+	if err := Reset(db, dir, WithDryRun()); err == nil {
+		t.Errorf("invalid dry run reset error: got:nil want:error")
+	}
+}
+
+func TestFix(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"00001_create_users.sql", "20220703120000_create_posts.sql", "20220704090000_create_tags.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("-- +goose Up\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Fix(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"00001_create_users.sql", "00002_create_posts.sql", "00003_create_tags.sql"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("invalid fixed file %s: %v", name, err)
+		}
+	}
+}
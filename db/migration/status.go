@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/glugox/unogo/log"
+)
+
+// Status prints the status of all migrations.
+func Status(db *sql.DB, dir string, opts ...OptionsFunc) error {
+	option := &options{}
+	for _, f := range opts {
+		f(option)
+	}
+	migrations, err := CollectMigrations(dir, minVersion, maxVersion)
+	if err != nil {
+		return fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	if option.noVersioning {
+		log.Info("    Applied At                  Migration")
+		log.Info("    =======================================")
+		for _, current := range migrations {
+			log.Info("    %-24s -- %v", "no versioning", filepath.Base(current.Source))
+		}
+		return nil
+	}
+
+	// must ensure that the version table exists if we're running on a pristine DB
+	if _, err := EnsureDBVersion(db); err != nil {
+		return fmt.Errorf("failed to ensure DB version: %w", err)
+	}
+
+	log.Info("    Applied At                  Migration")
+	log.Info("    =======================================")
+	for _, migration := range migrations {
+		if err := printMigrationStatus(db, migration.Version, filepath.Base(migration.Source)); err != nil {
+			return fmt.Errorf("failed to print status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func printMigrationStatus(db *sql.DB, version int64, script string) error {
+	var row MigrationRecord
+	err := db.QueryRow(GetDialect().migrationSQL(), version).Scan(&row.TStamp, &row.IsApplied)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to query the latest migration: %w", err)
+	}
+
+	appliedAt := "Pending"
+	if row.IsApplied {
+		appliedAt = row.TStamp.Format(time.ANSIC)
+	}
+
+	log.Info("    %-24s -- %v", appliedAt, script)
+	return nil
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glugox/unogo/log"
+	"github.com/glugox/unogo/log/record"
+)
+
+// recordHandler Keeps the messages of the records it handles.
+type recordHandler struct {
+	messages []string
+}
+
+func (h *recordHandler) IsHandling(r record.Record) bool {
+	return true
+}
+
+func (h *recordHandler) Handle(r record.Record) bool {
+	h.messages = append(h.messages, r.Message)
+	return true
+}
+
+func TestStatus(t *testing.T) {
+	db, dir := newTestMigrations(t, "users", "posts")
+
+	if err := UpTo(db, dir, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &recordHandler{}
+	logger := log.GetLogger()
+	log.SetLogger(log.NewLogger("testing", record.DEBUG).PushHandler(h))
+	defer log.SetLogger(logger)
+
+	if err := Status(db, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[string]string{}
+	for _, message := range h.messages {
+		if parts := strings.SplitN(message, " -- ", 2); len(parts) == 2 {
+			statuses[parts[1]] = strings.TrimSpace(parts[0])
+		}
+	}
+
+	if len(statuses) != 2 {
+		t.Fatalf("invalid status lines: got:%q", h.messages)
+	}
+	if _, err := time.Parse(time.ANSIC, statuses["00001_create_users.sql"]); err != nil {
+		t.Errorf("invalid applied at: got:%s want:%s", statuses["00001_create_users.sql"], time.ANSIC)
+	}
+	if got := statuses["00002_create_posts.sql"]; got != "Pending" {
+		t.Errorf("invalid pending status: got:%s want:%s", got, "Pending")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/glugox/unogo/log"
 )
@@ -14,6 +15,8 @@ type options struct {
 	allowMissing bool
 	applyUpByOne bool
 	noVersioning bool
+	dryRun       bool
+	lockTimeout  time.Duration
 }
 
 type OptionsFunc func(o *options)
@@ -26,6 +29,18 @@ func WithNoVersioning() OptionsFunc {
 	return func(o *options) { o.noVersioning = true }
 }
 
+// WithDryRun prints the migrations that would run, with their statements,
+// instead of running them.
+func WithDryRun() OptionsFunc {
+	return func(o *options) { o.dryRun = true }
+}
+
+// WithLockTimeout sets how long to wait for another runner to release the
+// migration lock, a minute by default.
+func WithLockTimeout(timeout time.Duration) OptionsFunc {
+	return func(o *options) { o.lockTimeout = timeout }
+}
+
 func withApplyUpByOne() OptionsFunc {
 	return func(o *options) { o.applyUpByOne = true }
 }
@@ -36,11 +51,26 @@ func UpTo(db *sql.DB, dir string, version int64, opts ...OptionsFunc) error {
 	for _, f := range opts {
 		f(option)
 	}
+	return withLock(db, option, func() error {
+		return upTo(db, dir, version, option)
+	})
+}
+
+// upTo migrates up to a specific version while holding the migration lock.
+func upTo(db *sql.DB, dir string, version int64, option *options) error {
 	foundMigrations, err := CollectMigrations(dir, minVersion, version)
 	if err != nil {
 		return err
 	}
 
+	if option.dryRun {
+		pending, err := pendingMigrations(db, foundMigrations, version, option)
+		if err != nil {
+			return err
+		}
+		return dryRun(pending, true)
+	}
+
 	if option.noVersioning {
 		if len(foundMigrations) == 0 {
 			return nil
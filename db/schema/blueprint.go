@@ -0,0 +1,466 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+// Blueprint The definition of a table being created or changed. Columns and
+// commands are collected by the callback given to Builder.Create or
+// Builder.Table and compiled into SQL by the grammar of the dialect.
+type Blueprint struct {
+	table    string
+	creating bool
+	columns  []*ColumnDefinition
+	commands []*command
+}
+
+// ColumnDefinition A column of a blueprint. Its modifiers return the column,
+// so that they can be chained:
+//
+//	t.String("email").Nullable().Unique()
+type ColumnDefinition struct {
+	Name          string
+	Type          string
+	Length        int
+	Total         int
+	Places        int
+	IsNullable    bool
+	IsUnsigned    bool
+	AutoIncrement bool
+	HasDefault    bool
+	DefaultValue  interface{}
+
+	blueprint *Blueprint
+}
+
+// ForeignKeyDefinition A foreign key constraint of a blueprint.
+type ForeignKeyDefinition struct {
+	Name              string
+	Columns           []string
+	ReferencedColumns []string
+	ReferencedTable   string
+	OnDeleteAction    string
+	OnUpdateAction    string
+}
+
+// command A statement of a blueprint other than its column definitions.
+type command struct {
+	name    string
+	index   string
+	columns []string
+	from    string
+	to      string
+	foreign *ForeignKeyDefinition
+}
+
+// NewBlueprint Create a blueprint of the table.
+func NewBlueprint(table string) *Blueprint {
+	return &Blueprint{table: table}
+}
+
+// GetTable Get the name of the table of the blueprint.
+func (b *Blueprint) GetTable() string {
+	return b.table
+}
+
+// GetColumns Get the columns defined by the blueprint.
+func (b *Blueprint) GetColumns() []*ColumnDefinition {
+	return b.columns
+}
+
+// Creating Determine if the blueprint creates its table.
+func (b *Blueprint) Creating() bool {
+	return b.creating
+}
+
+// create Indicate that the table should be created.
+func (b *Blueprint) create() *Blueprint {
+	b.creating = true
+	return b.addCommand(&command{name: "create"})
+}
+
+// drop Indicate that the table should be dropped.
+func (b *Blueprint) drop() *Blueprint {
+	return b.addCommand(&command{name: "drop"})
+}
+
+// dropIfExists Indicate that the table should be dropped if it exists.
+func (b *Blueprint) dropIfExists() *Blueprint {
+	return b.addCommand(&command{name: "dropIfExists"})
+}
+
+// rename Indicate that the table should be renamed.
+func (b *Blueprint) rename(to string) *Blueprint {
+	return b.addCommand(&command{name: "rename", from: b.table, to: to})
+}
+
+// ID Add an auto-incrementing big integer primary key "id" column, or a
+// column of the given name.
+func (b *Blueprint) ID(column ...string) *ColumnDefinition {
+	name := "id"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	return b.BigIncrements(name)
+}
+
+// Increments Add an auto-incrementing integer primary key column.
+func (b *Blueprint) Increments(column string) *ColumnDefinition {
+	return b.UnsignedInteger(column).autoIncrement()
+}
+
+// BigIncrements Add an auto-incrementing big integer primary key column.
+func (b *Blueprint) BigIncrements(column string) *ColumnDefinition {
+	return b.UnsignedBigInteger(column).autoIncrement()
+}
+
+// TinyInteger Add a tiny integer column.
+func (b *Blueprint) TinyInteger(column string) *ColumnDefinition {
+	return b.addColumn("tinyInteger", column)
+}
+
+// SmallInteger Add a small integer column.
+func (b *Blueprint) SmallInteger(column string) *ColumnDefinition {
+	return b.addColumn("smallInteger", column)
+}
+
+// Integer Add an integer column.
+func (b *Blueprint) Integer(column string) *ColumnDefinition {
+	return b.addColumn("integer", column)
+}
+
+// BigInteger Add a big integer column.
+func (b *Blueprint) BigInteger(column string) *ColumnDefinition {
+	return b.addColumn("bigInteger", column)
+}
+
+// UnsignedInteger Add an unsigned integer column.
+func (b *Blueprint) UnsignedInteger(column string) *ColumnDefinition {
+	return b.Integer(column).Unsigned()
+}
+
+// UnsignedBigInteger Add an unsigned big integer column.
+func (b *Blueprint) UnsignedBigInteger(column string) *ColumnDefinition {
+	return b.BigInteger(column).Unsigned()
+}
+
+// ForeignId Add an unsigned big integer column referencing the "id" of
+// another table, see ColumnDefinition.Constrained.
+func (b *Blueprint) ForeignId(column string) *ColumnDefinition {
+	return b.UnsignedBigInteger(column)
+}
+
+// Float Add a single precision floating point column.
+func (b *Blueprint) Float(column string) *ColumnDefinition {
+	return b.addColumn("float", column)
+}
+
+// Double Add a double precision floating point column.
+func (b *Blueprint) Double(column string) *ColumnDefinition {
+	return b.addColumn("double", column)
+}
+
+// Decimal Add a decimal column with the given total digits, 8 by default,
+// and decimal places, 2 by default.
+func (b *Blueprint) Decimal(column string, args ...int) *ColumnDefinition {
+	c := b.addColumn("decimal", column)
+	c.Total, c.Places = 8, 2
+	if len(args) > 0 {
+		c.Total = args[0]
+	}
+	if len(args) > 1 {
+		c.Places = args[1]
+	}
+	return c
+}
+
+// Boolean Add a boolean column.
+func (b *Blueprint) Boolean(column string) *ColumnDefinition {
+	return b.addColumn("boolean", column)
+}
+
+// Char Add a fixed length string column, 255 long by default.
+func (b *Blueprint) Char(column string, length ...int) *ColumnDefinition {
+	c := b.addColumn("char", column)
+	c.Length = stringLength(length)
+	return c
+}
+
+// String Add a variable length string column, up to 255 long by default.
+func (b *Blueprint) String(column string, length ...int) *ColumnDefinition {
+	c := b.addColumn("string", column)
+	c.Length = stringLength(length)
+	return c
+}
+
+// Text Add a text column.
+func (b *Blueprint) Text(column string) *ColumnDefinition {
+	return b.addColumn("text", column)
+}
+
+// LongText Add a long text column.
+func (b *Blueprint) LongText(column string) *ColumnDefinition {
+	return b.addColumn("longText", column)
+}
+
+// Json Add a JSON column.
+func (b *Blueprint) Json(column string) *ColumnDefinition {
+	return b.addColumn("json", column)
+}
+
+// Binary Add a binary column.
+func (b *Blueprint) Binary(column string) *ColumnDefinition {
+	return b.addColumn("binary", column)
+}
+
+// Uuid Add a UUID column.
+func (b *Blueprint) Uuid(column string) *ColumnDefinition {
+	return b.addColumn("uuid", column)
+}
+
+// Date Add a date column.
+func (b *Blueprint) Date(column string) *ColumnDefinition {
+	return b.addColumn("date", column)
+}
+
+// DateTime Add a date-time column.
+func (b *Blueprint) DateTime(column string) *ColumnDefinition {
+	return b.addColumn("dateTime", column)
+}
+
+// Time Add a time column.
+func (b *Blueprint) Time(column string) *ColumnDefinition {
+	return b.addColumn("time", column)
+}
+
+// Timestamp Add a timestamp column.
+func (b *Blueprint) Timestamp(column string) *ColumnDefinition {
+	return b.addColumn("timestamp", column)
+}
+
+// Timestamps Add the nullable created_at and updated_at columns stamped by
+// the orm.
+func (b *Blueprint) Timestamps() {
+	b.Timestamp("created_at").Nullable()
+	b.Timestamp("updated_at").Nullable()
+}
+
+// SoftDeletes Add the nullable deleted_at column of soft deleted models.
+func (b *Blueprint) SoftDeletes() *ColumnDefinition {
+	return b.Timestamp("deleted_at").Nullable()
+}
+
+// DropColumn Drop the given columns.
+func (b *Blueprint) DropColumn(columns ...string) {
+	b.addCommand(&command{name: "dropColumn", columns: columns})
+}
+
+// RenameColumn Rename a column.
+func (b *Blueprint) RenameColumn(from string, to string) {
+	b.addCommand(&command{name: "renameColumn", from: from, to: to})
+}
+
+// DropTimestamps Drop the created_at and updated_at columns.
+func (b *Blueprint) DropTimestamps() {
+	b.DropColumn("created_at", "updated_at")
+}
+
+// DropSoftDeletes Drop the deleted_at column.
+func (b *Blueprint) DropSoftDeletes() {
+	b.DropColumn("deleted_at")
+}
+
+// Primary Add a primary key on the given columns.
+func (b *Blueprint) Primary(columns ...string) {
+	b.addIndex("primary", "", columns)
+}
+
+// Unique Add a unique index on the given columns.
+func (b *Blueprint) Unique(columns ...string) {
+	b.addIndex("unique", "", columns)
+}
+
+// Index Add an index on the given columns.
+func (b *Blueprint) Index(columns ...string) {
+	b.addIndex("index", "", columns)
+}
+
+// UniqueIndex Add a unique index with the given name.
+func (b *Blueprint) UniqueIndex(name string, columns ...string) {
+	b.addIndex("unique", name, columns)
+}
+
+// NamedIndex Add an index with the given name.
+func (b *Blueprint) NamedIndex(name string, columns ...string) {
+	b.addIndex("index", name, columns)
+}
+
+// Foreign Add a foreign key on the given columns. The referenced table and
+// columns are set on the returned definition:
+//
+//	t.Foreign("user_id").References("id").On("users").OnDelete("cascade")
+func (b *Blueprint) Foreign(columns ...string) *ForeignKeyDefinition {
+	foreign := &ForeignKeyDefinition{
+		Name:    b.indexName("foreign", columns),
+		Columns: columns,
+	}
+	b.addCommand(&command{name: "foreign", index: foreign.Name, columns: columns, foreign: foreign})
+	return foreign
+}
+
+// DropPrimary Drop the primary key of the given name.
+func (b *Blueprint) DropPrimary(name string) {
+	b.addCommand(&command{name: "dropPrimary", index: name})
+}
+
+// DropIndex Drop the index of the given name.
+func (b *Blueprint) DropIndex(name string) {
+	b.addCommand(&command{name: "dropIndex", index: name})
+}
+
+// DropUnique Drop the unique index of the given name.
+func (b *Blueprint) DropUnique(name string) {
+	b.addCommand(&command{name: "dropUnique", index: name})
+}
+
+// DropForeign Drop the foreign key of the given name.
+func (b *Blueprint) DropForeign(name string) {
+	b.addCommand(&command{name: "dropForeign", index: name})
+}
+
+// addColumn Add a column of the given type to the blueprint.
+func (b *Blueprint) addColumn(typ string, name string) *ColumnDefinition {
+	column := &ColumnDefinition{Name: name, Type: typ, blueprint: b}
+	b.columns = append(b.columns, column)
+	return column
+}
+
+// addIndex Add an index command, named after the table, the columns and
+// the type of the index unless a name is given.
+func (b *Blueprint) addIndex(typ string, name string, columns []string) {
+	if name == "" {
+		name = b.indexName(typ, columns)
+	}
+	b.addCommand(&command{name: typ, index: name, columns: columns})
+}
+
+func (b *Blueprint) addCommand(c *command) *Blueprint {
+	b.commands = append(b.commands, c)
+	return b
+}
+
+// indexName Get the default name of an index, such as users_email_unique.
+func (b *Blueprint) indexName(typ string, columns []string) string {
+	name := strings.ToLower(b.table + "_" + strings.Join(columns, "_") + "_" + typ)
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// Nullable Allow NULL values in the column.
+func (c *ColumnDefinition) Nullable() *ColumnDefinition {
+	c.IsNullable = true
+	return c
+}
+
+// Unsigned Make an integer column unsigned, on the databases that have
+// unsigned integers.
+func (c *ColumnDefinition) Unsigned() *ColumnDefinition {
+	c.IsUnsigned = true
+	return c
+}
+
+// Default Set the default value of the column. A query.Expr value is used as
+// is rather than quoted.
+func (c *ColumnDefinition) Default(value interface{}) *ColumnDefinition {
+	c.HasDefault = true
+	c.DefaultValue = value
+	return c
+}
+
+// UseCurrent Default a timestamp column to the current timestamp.
+func (c *ColumnDefinition) UseCurrent() *ColumnDefinition {
+	return c.Default(query.Expr("CURRENT_TIMESTAMP"))
+}
+
+// Primary Add a primary key on the column.
+func (c *ColumnDefinition) Primary() *ColumnDefinition {
+	c.blueprint.Primary(c.Name)
+	return c
+}
+
+// Unique Add a unique index on the column.
+func (c *ColumnDefinition) Unique() *ColumnDefinition {
+	c.blueprint.Unique(c.Name)
+	return c
+}
+
+// Index Add an index on the column.
+func (c *ColumnDefinition) Index() *ColumnDefinition {
+	c.blueprint.Index(c.Name)
+	return c
+}
+
+// Constrained Add a foreign key on the column referencing the "id" column of
+// the given table.
+func (c *ColumnDefinition) Constrained(table string) *ForeignKeyDefinition {
+	return c.blueprint.Foreign(c.Name).References("id").On(table)
+}
+
+// autoIncrement Make the column an auto-incrementing primary key.
+func (c *ColumnDefinition) autoIncrement() *ColumnDefinition {
+	c.AutoIncrement = true
+	return c
+}
+
+// isInteger Determine if the column is of one of the integer types.
+func (c *ColumnDefinition) isInteger() bool {
+	switch c.Type {
+	case "tinyInteger", "smallInteger", "integer", "bigInteger":
+		return true
+	}
+	return false
+}
+
+// References Set the referenced columns of the foreign key.
+func (f *ForeignKeyDefinition) References(columns ...string) *ForeignKeyDefinition {
+	f.ReferencedColumns = columns
+	return f
+}
+
+// On Set the referenced table of the foreign key.
+func (f *ForeignKeyDefinition) On(table string) *ForeignKeyDefinition {
+	f.ReferencedTable = table
+	return f
+}
+
+// OnDelete Set the action taken when the referenced row is deleted, such as
+// "cascade", "set null" or "restrict".
+func (f *ForeignKeyDefinition) OnDelete(action string) *ForeignKeyDefinition {
+	f.OnDeleteAction = action
+	return f
+}
+
+// OnUpdate Set the action taken when the referenced row is updated.
+func (f *ForeignKeyDefinition) OnUpdate(action string) *ForeignKeyDefinition {
+	f.OnUpdateAction = action
+	return f
+}
+
+// CascadeOnDelete Delete the rows referencing a deleted row.
+func (f *ForeignKeyDefinition) CascadeOnDelete() *ForeignKeyDefinition {
+	return f.OnDelete("cascade")
+}
+
+// NullOnDelete Set the referencing columns to NULL when the referenced row
+// is deleted.
+func (f *ForeignKeyDefinition) NullOnDelete() *ForeignKeyDefinition {
+	return f.OnDelete("set null")
+}
+
+func stringLength(length []int) int {
+	if len(length) > 0 && length[0] > 0 {
+		return length[0]
+	}
+	return 255
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/glugox/unogo/db/migration"
+)
+
+// Executor Runs statements, such as the *sql.Tx of a Go migration or a *sql.DB.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Builder Creates and changes tables from blueprints:
+//
+//	func upCreateUsersTable(tx *sql.Tx) error {
+//		return schema.New(tx).Create("users", func(t *schema.Blueprint) {
+//			t.ID()
+//			t.String("email").Unique()
+//			t.Timestamps()
+//		})
+//	}
+type Builder struct {
+	conn    Executor
+	grammar Grammar
+	err     error
+}
+
+// New Create a schema builder running its statements on conn. The
+// statements are compiled for the dialect set by migration.SetDialect, or for
+// the given one.
+func New(conn Executor, dialect ...string) *Builder {
+	name := migration.GetDialectName()
+	if len(dialect) > 0 {
+		name = dialect[0]
+	}
+
+	g, err := NewGrammar(name)
+
+	return &Builder{conn: conn, grammar: g, err: err}
+}
+
+// GetGrammar Get the grammar the blueprints are compiled with.
+func (s *Builder) GetGrammar() Grammar {
+	return s.grammar
+}
+
+// Create Create a table defined by the callback.
+func (s *Builder) Create(table string, callback func(t *Blueprint)) error {
+	blueprint := NewBlueprint(table).create()
+	callback(blueprint)
+	return s.build(blueprint)
+}
+
+// Table Change a table as defined by the callback.
+func (s *Builder) Table(table string, callback func(t *Blueprint)) error {
+	blueprint := NewBlueprint(table)
+	callback(blueprint)
+	return s.build(blueprint)
+}
+
+// Drop Drop a table.
+func (s *Builder) Drop(table string) error {
+	return s.build(NewBlueprint(table).drop())
+}
+
+// DropIfExists Drop a table if it exists.
+func (s *Builder) DropIfExists(table string) error {
+	return s.build(NewBlueprint(table).dropIfExists())
+}
+
+// Rename Rename a table.
+func (s *Builder) Rename(from string, to string) error {
+	return s.build(NewBlueprint(from).rename(to))
+}
+
+// build Compile the blueprint and run its statements.
+func (s *Builder) build(blueprint *Blueprint) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	statements, err := s.grammar.Compile(blueprint)
+	if err != nil {
+		return fmt.Errorf("table %s: %w", blueprint.GetTable(), err)
+	}
+
+	for _, statement := range statements {
+		if _, err := s.conn.Exec(statement); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", statement, err)
+		}
+	}
+
+	return nil
+}
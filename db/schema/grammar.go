@@ -0,0 +1,289 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glugox/unogo/orm/grammar"
+)
+
+// Grammar Compiles blueprints into the SQL of a database.
+type Grammar interface {
+	// Compile Compile the blueprint into the statements that build it.
+	Compile(blueprint *Blueprint) ([]string, error)
+}
+
+// NewGrammar Create the schema grammar of a dialect, named as for
+// migration.SetDialect.
+func NewGrammar(dialect string) (Grammar, error) {
+	switch dialect {
+	case "postgres", "pgx":
+		return NewPostgresGrammar(), nil
+	case "redshift":
+		return NewRedshiftGrammar(), nil
+	case "mysql", "tidb":
+		return NewMySqlGrammar(), nil
+	case "sqlite3", "sqlite":
+		return NewSqliteGrammar(), nil
+	case "mssql":
+		return NewSqlServerGrammar(), nil
+	case "clickhouse":
+		return nil, fmt.Errorf("%q: dialect not supported by the schema builder", dialect)
+	}
+
+	return nil, fmt.Errorf("%q: unknown dialect", dialect)
+}
+
+// dialect The pieces of DDL that differ between databases. Each grammar
+// implements it and registers itself on the embedded BaseGrammar, so that the
+// shared compilers below can call back into the concrete grammar.
+type dialect interface {
+	// wrapValue Wrap a single string in keyword identifiers.
+	wrapValue(value string) string
+
+	// typeName Get the SQL type of a column.
+	typeName(column *ColumnDefinition) string
+
+	// compileAutoIncrement Get the modifier making a column an
+	// auto-incrementing primary key.
+	compileAutoIncrement(column *ColumnDefinition) string
+
+	// compileAdd Compile the adding of a column to an existing table.
+	compileAdd(table string, column *ColumnDefinition) string
+
+	// compileRename Compile the renaming of a table.
+	compileRename(from string, to string) string
+
+	// compileRenameColumn Compile the renaming of a column.
+	compileRenameColumn(table string, from string, to string) string
+
+	// compileDropIndex Compile the dropping of an index.
+	compileDropIndex(table string, name string) string
+
+	// compileAddPrimary Compile the adding of a primary key to an existing table.
+	compileAddPrimary(table string, name string, columns []string) (string, error)
+
+	// compileAddForeign Compile the adding of a foreign key to an existing table.
+	compileAddForeign(table string, foreign *ForeignKeyDefinition) (string, error)
+
+	// compileDropPrimary Compile the dropping of a primary key.
+	compileDropPrimary(table string, name string) (string, error)
+
+	// compileDropForeign Compile the dropping of a foreign key.
+	compileDropForeign(table string, name string) (string, error)
+}
+
+type BaseGrammar struct {
+	dialect dialect
+}
+
+// Compile Compile the blueprint into the statements that build it. New
+// columns come first, then the commands in the order they were added.
+func (g *BaseGrammar) Compile(b *Blueprint) ([]string, error) {
+	var statements []string
+
+	if !b.creating {
+		for _, column := range b.columns {
+			statements = append(statements, g.dialect.compileAdd(b.table, column))
+		}
+	}
+
+	for _, c := range b.commands {
+		sql, err := g.compileCommand(b, c)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, sql...)
+	}
+
+	return statements, nil
+}
+
+func (g *BaseGrammar) compileCommand(b *Blueprint, c *command) ([]string, error) {
+	var (
+		sql string
+		err error
+	)
+
+	switch c.name {
+	case "create":
+		sql = g.compileCreate(b)
+	case "primary":
+		if b.creating {
+			return nil, nil // part of the create statement
+		}
+		sql, err = g.dialect.compileAddPrimary(b.table, c.index, c.columns)
+	case "foreign":
+		if b.creating {
+			return nil, nil // part of the create statement
+		}
+		sql, err = g.dialect.compileAddForeign(b.table, c.foreign)
+	case "unique":
+		sql = "CREATE UNIQUE INDEX " + g.Wrap(c.index) + " ON " + g.Wrap(b.table) + " (" + g.Columnize(c.columns) + ")"
+	case "index":
+		sql = "CREATE INDEX " + g.Wrap(c.index) + " ON " + g.Wrap(b.table) + " (" + g.Columnize(c.columns) + ")"
+	case "dropColumn":
+		var statements []string
+		for _, column := range c.columns {
+			statements = append(statements, "ALTER TABLE "+g.Wrap(b.table)+" DROP COLUMN "+g.Wrap(column))
+		}
+		return statements, nil
+	case "renameColumn":
+		sql = g.dialect.compileRenameColumn(b.table, c.from, c.to)
+	case "dropIndex", "dropUnique":
+		sql = g.dialect.compileDropIndex(b.table, c.index)
+	case "dropPrimary":
+		sql, err = g.dialect.compileDropPrimary(b.table, c.index)
+	case "dropForeign":
+		sql, err = g.dialect.compileDropForeign(b.table, c.index)
+	case "drop":
+		sql = "DROP TABLE " + g.Wrap(b.table)
+	case "dropIfExists":
+		sql = "DROP TABLE IF EXISTS " + g.Wrap(b.table)
+	case "rename":
+		sql = g.dialect.compileRename(c.from, c.to)
+	default:
+		return nil, fmt.Errorf("unknown schema command %q", c.name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return []string{sql}, nil
+}
+
+// compileCreate Compile a create table statement, with the primary and
+// foreign keys of the blueprint as table constraints.
+func (g *BaseGrammar) compileCreate(b *Blueprint) string {
+	var definitions []string
+
+	for _, column := range b.columns {
+		definitions = append(definitions, g.compileColumn(column))
+	}
+
+	for _, c := range b.commands {
+		switch c.name {
+		case "primary":
+			definitions = append(definitions, "CONSTRAINT "+g.Wrap(c.index)+" PRIMARY KEY ("+g.Columnize(c.columns)+")")
+		case "foreign":
+			definitions = append(definitions, g.compileForeign(c.foreign))
+		}
+	}
+
+	return "CREATE TABLE " + g.Wrap(b.table) + " (" + strings.Join(definitions, ", ") + ")"
+}
+
+// compileColumn Compile the definition of a column.
+func (g *BaseGrammar) compileColumn(column *ColumnDefinition) string {
+	sql := g.Wrap(column.Name) + " " + g.dialect.typeName(column)
+
+	if column.IsNullable {
+		sql += " NULL"
+	} else {
+		sql += " NOT NULL"
+	}
+
+	if column.HasDefault {
+		sql += " DEFAULT " + g.defaultValue(column.DefaultValue)
+	}
+
+	if column.AutoIncrement {
+		sql += g.dialect.compileAutoIncrement(column)
+	}
+
+	return sql
+}
+
+// compileForeign Compile a foreign key constraint.
+func (g *BaseGrammar) compileForeign(foreign *ForeignKeyDefinition) string {
+	sql := "CONSTRAINT " + g.Wrap(foreign.Name) +
+		" FOREIGN KEY (" + g.Columnize(foreign.Columns) + ")" +
+		" REFERENCES " + g.Wrap(foreign.ReferencedTable) + " (" + g.Columnize(foreign.ReferencedColumns) + ")"
+
+	if foreign.OnDeleteAction != "" {
+		sql += " ON DELETE " + strings.ToUpper(foreign.OnDeleteAction)
+	}
+	if foreign.OnUpdateAction != "" {
+		sql += " ON UPDATE " + strings.ToUpper(foreign.OnUpdateAction)
+	}
+
+	return sql
+}
+
+// defaultValue Format the default value of a column. Strings and booleans
+// are quoted, expressions are used as is.
+func (g *BaseGrammar) defaultValue(value interface{}) string {
+	switch v := value.(type) {
+	case grammar.Expression:
+		return fmt.Sprint(v.GetValue())
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "'1'"
+		}
+		return "'0'"
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'"
+	}
+	return fmt.Sprint(value)
+}
+
+// Wrap Wrap a table, column or index name in keyword identifiers.
+func (g *BaseGrammar) Wrap(value string) string {
+	segments := strings.Split(value, ".")
+	for i, segment := range segments {
+		segments[i] = g.dialect.wrapValue(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// Columnize Convert a list of column names into a delimited string.
+func (g *BaseGrammar) Columnize(columns []string) string {
+	wrapped := make([]string, len(columns))
+	for i, column := range columns {
+		wrapped[i] = g.Wrap(column)
+	}
+	return strings.Join(wrapped, ", ")
+}
+
+// wrapValue Wrap a single string in double quotes.
+func (g *BaseGrammar) wrapValue(value string) string {
+	return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+}
+
+func (g *BaseGrammar) compileAutoIncrement(column *ColumnDefinition) string {
+	return " PRIMARY KEY"
+}
+
+func (g *BaseGrammar) compileAdd(table string, column *ColumnDefinition) string {
+	return "ALTER TABLE " + g.Wrap(table) + " ADD COLUMN " + g.compileColumn(column)
+}
+
+func (g *BaseGrammar) compileRename(from string, to string) string {
+	return "ALTER TABLE " + g.Wrap(from) + " RENAME TO " + g.Wrap(to)
+}
+
+func (g *BaseGrammar) compileRenameColumn(table string, from string, to string) string {
+	return "ALTER TABLE " + g.Wrap(table) + " RENAME COLUMN " + g.Wrap(from) + " TO " + g.Wrap(to)
+}
+
+func (g *BaseGrammar) compileDropIndex(table string, name string) string {
+	return "DROP INDEX " + g.Wrap(name)
+}
+
+func (g *BaseGrammar) compileAddPrimary(table string, name string, columns []string) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " ADD CONSTRAINT " + g.Wrap(name) + " PRIMARY KEY (" + g.Columnize(columns) + ")", nil
+}
+
+func (g *BaseGrammar) compileAddForeign(table string, foreign *ForeignKeyDefinition) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " ADD " + g.compileForeign(foreign), nil
+}
+
+func (g *BaseGrammar) compileDropPrimary(table string, name string) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " DROP CONSTRAINT " + g.Wrap(name), nil
+}
+
+func (g *BaseGrammar) compileDropForeign(table string, name string) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " DROP CONSTRAINT " + g.Wrap(name), nil
+}
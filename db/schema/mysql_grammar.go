@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MySqlGrammar struct {
+	BaseGrammar
+}
+
+// NewMySqlGrammar Create a new MySQL schema grammar.
+func NewMySqlGrammar() *MySqlGrammar {
+	g := &MySqlGrammar{}
+	g.dialect = g
+	return g
+}
+
+// wrapValue Wrap a single string in backticks.
+func (g *MySqlGrammar) wrapValue(value string) string {
+	return "`" + strings.Replace(value, "`", "``", -1) + "`"
+}
+
+// typeName Get the SQL type of a column. Integers may be unsigned.
+func (g *MySqlGrammar) typeName(column *ColumnDefinition) string {
+	var typ string
+
+	switch column.Type {
+	case "tinyInteger":
+		typ = "tinyint"
+	case "smallInteger":
+		typ = "smallint"
+	case "integer":
+		typ = "int"
+	case "bigInteger":
+		typ = "bigint"
+	case "float":
+		typ = "float"
+	case "double":
+		typ = "double"
+	case "decimal":
+		typ = fmt.Sprintf("decimal(%d, %d)", column.Total, column.Places)
+	case "boolean":
+		typ = "tinyint(1)"
+	case "char":
+		typ = fmt.Sprintf("char(%d)", column.Length)
+	case "string":
+		typ = fmt.Sprintf("varchar(%d)", column.Length)
+	case "text":
+		typ = "text"
+	case "longText":
+		typ = "longtext"
+	case "json":
+		typ = "json"
+	case "binary":
+		typ = "blob"
+	case "uuid":
+		typ = "char(36)"
+	case "date":
+		typ = "date"
+	case "dateTime":
+		typ = "datetime"
+	case "time":
+		typ = "time"
+	case "timestamp":
+		typ = "timestamp"
+	}
+
+	if column.IsUnsigned && column.isInteger() {
+		typ += " unsigned"
+	}
+
+	return typ
+}
+
+func (g *MySqlGrammar) compileAutoIncrement(column *ColumnDefinition) string {
+	return " AUTO_INCREMENT PRIMARY KEY"
+}
+
+func (g *MySqlGrammar) compileDropIndex(table string, name string) string {
+	return "DROP INDEX " + g.Wrap(name) + " ON " + g.Wrap(table)
+}
+
+func (g *MySqlGrammar) compileDropPrimary(table string, name string) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " DROP PRIMARY KEY", nil
+}
+
+func (g *MySqlGrammar) compileDropForeign(table string, name string) (string, error) {
+	return "ALTER TABLE " + g.Wrap(table) + " DROP FOREIGN KEY " + g.Wrap(name), nil
+}
@@ -0,0 +1,83 @@
+package schema
+
+import "fmt"
+
+type PostgresGrammar struct {
+	BaseGrammar
+}
+
+// NewPostgresGrammar Create a new PostgreSQL schema grammar.
+func NewPostgresGrammar() *PostgresGrammar {
+	g := &PostgresGrammar{}
+	g.dialect = g
+	return g
+}
+
+// typeName Get the SQL type of a column. Auto-incrementing integers are
+// serials.
+func (g *PostgresGrammar) typeName(column *ColumnDefinition) string {
+	switch column.Type {
+	case "tinyInteger", "smallInteger":
+		if column.AutoIncrement {
+			return "smallserial"
+		}
+		return "smallint"
+	case "integer":
+		if column.AutoIncrement {
+			return "serial"
+		}
+		return "integer"
+	case "bigInteger":
+		if column.AutoIncrement {
+			return "bigserial"
+		}
+		return "bigint"
+	case "float":
+		return "real"
+	case "double":
+		return "double precision"
+	case "decimal":
+		return fmt.Sprintf("decimal(%d, %d)", column.Total, column.Places)
+	case "boolean":
+		return "boolean"
+	case "char":
+		return fmt.Sprintf("char(%d)", column.Length)
+	case "string":
+		return fmt.Sprintf("varchar(%d)", column.Length)
+	case "text", "longText":
+		return "text"
+	case "json":
+		return "json"
+	case "binary":
+		return "bytea"
+	case "uuid":
+		return "uuid"
+	case "date":
+		return "date"
+	case "dateTime", "timestamp":
+		return "timestamp(0) without time zone"
+	case "time":
+		return "time(0) without time zone"
+	}
+	return ""
+}
+
+type RedshiftGrammar struct {
+	PostgresGrammar
+}
+
+// NewRedshiftGrammar Create a new Redshift schema grammar.
+func NewRedshiftGrammar() *RedshiftGrammar {
+	g := &RedshiftGrammar{}
+	g.dialect = g
+	return g
+}
+
+// typeName Get the SQL type of a column. Redshift has no serials,
+// auto-incrementing integers are identity columns.
+func (g *RedshiftGrammar) typeName(column *ColumnDefinition) string {
+	if column.AutoIncrement {
+		return g.PostgresGrammar.typeName(&ColumnDefinition{Type: column.Type}) + " IDENTITY(1, 1)"
+	}
+	return g.PostgresGrammar.typeName(column)
+}
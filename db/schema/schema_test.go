@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/glugox/unogo/orm/driver/sqlite"
+	"github.com/glugox/unogo/orm/query"
+)
+
+func createPosts(t *Blueprint) {
+	t.ID()
+	t.ForeignId("user_id").Constrained("users").CascadeOnDelete()
+	t.String("title", 100).Unique()
+	t.Boolean("draft").Default(true)
+	t.Decimal("rating", 3, 1).Nullable()
+	t.Timestamp("published_at").Default(query.Expr("CURRENT_TIMESTAMP"))
+}
+
+func TestCompileCreate(t *testing.T) {
+	cases := []struct {
+		dialect string
+		want    []string
+	}{
+		{"mysql", []string{
+			"CREATE TABLE `posts` (`id` bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY, `user_id` bigint unsigned NOT NULL, " +
+				"`title` varchar(100) NOT NULL, `draft` tinyint(1) NOT NULL DEFAULT '1', `rating` decimal(3, 1) NULL, " +
+				"`published_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+				"CONSTRAINT `posts_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE)",
+			"CREATE UNIQUE INDEX `posts_title_unique` ON `posts` (`title`)",
+		}},
+		{"pgx", []string{
+			`CREATE TABLE "posts" ("id" bigserial NOT NULL PRIMARY KEY, "user_id" bigint NOT NULL, ` +
+				`"title" varchar(100) NOT NULL, "draft" boolean NOT NULL DEFAULT '1', "rating" decimal(3, 1) NULL, ` +
+				`"published_at" timestamp(0) without time zone NOT NULL DEFAULT CURRENT_TIMESTAMP, ` +
+				`CONSTRAINT "posts_user_id_foreign" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE)`,
+			`CREATE UNIQUE INDEX "posts_title_unique" ON "posts" ("title")`,
+		}},
+		{"sqlite3", []string{
+			`CREATE TABLE "posts" ("id" integer NOT NULL PRIMARY KEY AUTOINCREMENT, "user_id" integer NOT NULL, ` +
+				`"title" varchar NOT NULL, "draft" tinyint(1) NOT NULL DEFAULT '1', "rating" numeric NULL, ` +
+				`"published_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, ` +
+				`CONSTRAINT "posts_user_id_foreign" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE)`,
+			`CREATE UNIQUE INDEX "posts_title_unique" ON "posts" ("title")`,
+		}},
+		{"mssql", []string{
+			"CREATE TABLE [posts] ([id] bigint NOT NULL IDENTITY PRIMARY KEY, [user_id] bigint NOT NULL, " +
+				"[title] nvarchar(100) NOT NULL, [draft] bit NOT NULL DEFAULT '1', [rating] decimal(3, 1) NULL, " +
+				"[published_at] datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+				"CONSTRAINT [posts_user_id_foreign] FOREIGN KEY ([user_id]) REFERENCES [users] ([id]) ON DELETE CASCADE)",
+			"CREATE UNIQUE INDEX [posts_title_unique] ON [posts] ([title])",
+		}},
+	}
+
+	for _, c := range cases {
+		g, err := NewGrammar(c.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blueprint := NewBlueprint("posts").create()
+		createPosts(blueprint)
+
+		got, err := g.Compile(blueprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(c.want) {
+			t.Fatalf("invalid %s statements: got:%q want:%q", c.dialect, got, c.want)
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("invalid %s statement %d:\ngot:  %s\nwant: %s", c.dialect, i, got[i], c.want[i])
+			}
+		}
+	}
+
+	if _, err := NewGrammar("clickhouse"); err == nil {
+		t.Errorf("invalid clickhouse grammar: got:nil want:error")
+	}
+}
+
+func TestCompileTable(t *testing.T) {
+	blueprint := NewBlueprint("users")
+	blueprint.String("email").Nullable()
+	blueprint.DropColumn("nickname")
+	blueprint.RenameColumn("name", "full_name")
+	blueprint.DropForeign("users_team_id_foreign")
+	blueprint.DropIndex("users_name_index")
+
+	want := map[string][]string{
+		"mysql": {
+			"ALTER TABLE `users` ADD COLUMN `email` varchar(255) NULL",
+			"ALTER TABLE `users` DROP COLUMN `nickname`",
+			"ALTER TABLE `users` RENAME COLUMN `name` TO `full_name`",
+			"ALTER TABLE `users` DROP FOREIGN KEY `users_team_id_foreign`",
+			"DROP INDEX `users_name_index` ON `users`",
+		},
+		"postgres": {
+			`ALTER TABLE "users" ADD COLUMN "email" varchar(255) NULL`,
+			`ALTER TABLE "users" DROP COLUMN "nickname"`,
+			`ALTER TABLE "users" RENAME COLUMN "name" TO "full_name"`,
+			`ALTER TABLE "users" DROP CONSTRAINT "users_team_id_foreign"`,
+			`DROP INDEX "users_name_index"`,
+		},
+		"mssql": {
+			"ALTER TABLE [users] ADD [email] nvarchar(255) NULL",
+			"ALTER TABLE [users] DROP COLUMN [nickname]",
+			"EXEC sp_rename N'users.name', N'full_name', 'COLUMN'",
+			"ALTER TABLE [users] DROP CONSTRAINT [users_team_id_foreign]",
+			"DROP INDEX [users_name_index] ON [users]",
+		},
+	}
+
+	for dialect, statements := range want {
+		g, err := NewGrammar(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := g.Compile(blueprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(statements) {
+			t.Fatalf("invalid %s statements: got:%q want:%q", dialect, got, statements)
+		}
+		for i := range got {
+			if got[i] != statements[i] {
+				t.Errorf("invalid %s statement %d:\ngot:  %s\nwant: %s", dialect, i, got[i], statements[i])
+			}
+		}
+	}
+
+	if _, err := NewSqliteGrammar().Compile(blueprint); err == nil {
+		t.Errorf("invalid sqlite drop foreign: got:nil want:error")
+	}
+}
+
+func TestBuilderAgainstDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "schema.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s := New(db, "sqlite3")
+
+	err = s.Create("users", func(t *Blueprint) {
+		t.ID()
+		t.String("name")
+		t.String("email").Unique()
+		t.Timestamps()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Create("posts", createPosts); err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Table("users", func(t *Blueprint) {
+		t.Integer("age").Default(0)
+		t.RenameColumn("name", "full_name")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("INSERT INTO users (full_name, email) VALUES ('ana', 'ana@example.com')"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO users (full_name, email) VALUES ('bob', 'ana@example.com')"); err == nil {
+		t.Errorf("invalid unique email: got:nil want:error")
+	}
+	if _, err := db.Exec("INSERT INTO posts (user_id, title) VALUES (1, 'hello')"); err != nil {
+		t.Fatal(err)
+	}
+
+	var age int
+	var draft bool
+	if err := db.QueryRow("SELECT age FROM users").Scan(&age); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT draft FROM posts").Scan(&draft); err != nil {
+		t.Fatal(err)
+	}
+	if age != 0 || !draft {
+		t.Errorf("invalid defaults: got:%d,%v want:%d,%v", age, draft, 0, true)
+	}
+
+	if err := s.Rename("posts", "articles"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Drop("articles"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DropIfExists("articles"); err != nil {
+		t.Fatal(err)
+	}
+}
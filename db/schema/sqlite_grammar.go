@@ -0,0 +1,62 @@
+package schema
+
+import "errors"
+
+type SqliteGrammar struct {
+	BaseGrammar
+}
+
+// NewSqliteGrammar Create a new SQLite schema grammar.
+func NewSqliteGrammar() *SqliteGrammar {
+	g := &SqliteGrammar{}
+	g.dialect = g
+	return g
+}
+
+// typeName Get the SQL type of a column. Every integer is an "integer", the
+// only type an auto-incrementing primary key may have.
+func (g *SqliteGrammar) typeName(column *ColumnDefinition) string {
+	switch column.Type {
+	case "tinyInteger", "smallInteger", "integer", "bigInteger":
+		return "integer"
+	case "float", "double":
+		return "float"
+	case "decimal":
+		return "numeric"
+	case "boolean":
+		return "tinyint(1)"
+	case "char", "string", "uuid":
+		return "varchar"
+	case "text", "longText", "json":
+		return "text"
+	case "binary":
+		return "blob"
+	case "date":
+		return "date"
+	case "dateTime", "timestamp":
+		return "datetime"
+	case "time":
+		return "time"
+	}
+	return ""
+}
+
+func (g *SqliteGrammar) compileAutoIncrement(column *ColumnDefinition) string {
+	return " PRIMARY KEY AUTOINCREMENT"
+}
+
+func (g *SqliteGrammar) compileAddPrimary(table string, name string, columns []string) (string, error) {
+	return "", errors.New("sqlite can not add a primary key to an existing table")
+}
+
+func (g *SqliteGrammar) compileAddForeign(table string, foreign *ForeignKeyDefinition) (string, error) {
+	return "", errors.New("sqlite can not add a foreign key to an existing table")
+}
+
+func (g *SqliteGrammar) compileDropPrimary(table string, name string) (string, error) {
+	return "", errors.New("sqlite can not drop the primary key of a table")
+}
+
+func (g *SqliteGrammar) compileDropForeign(table string, name string) (string, error) {
+	return "", errors.New("sqlite can not drop a foreign key of a table")
+}
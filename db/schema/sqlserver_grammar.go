@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SqlServerGrammar struct {
+	BaseGrammar
+}
+
+// NewSqlServerGrammar Create a new SQL Server schema grammar.
+func NewSqlServerGrammar() *SqlServerGrammar {
+	g := &SqlServerGrammar{}
+	g.dialect = g
+	return g
+}
+
+// wrapValue Wrap a single string in brackets.
+func (g *SqlServerGrammar) wrapValue(value string) string {
+	return "[" + strings.Replace(value, "]", "]]", -1) + "]"
+}
+
+// typeName Get the SQL type of a column.
+func (g *SqlServerGrammar) typeName(column *ColumnDefinition) string {
+	switch column.Type {
+	case "tinyInteger":
+		return "tinyint"
+	case "smallInteger":
+		return "smallint"
+	case "integer":
+		return "int"
+	case "bigInteger":
+		return "bigint"
+	case "float":
+		return "real"
+	case "double":
+		return "float"
+	case "decimal":
+		return fmt.Sprintf("decimal(%d, %d)", column.Total, column.Places)
+	case "boolean":
+		return "bit"
+	case "char":
+		return fmt.Sprintf("nchar(%d)", column.Length)
+	case "string":
+		return fmt.Sprintf("nvarchar(%d)", column.Length)
+	case "text", "longText", "json":
+		return "nvarchar(max)"
+	case "binary":
+		return "varbinary(max)"
+	case "uuid":
+		return "uniqueidentifier"
+	case "date":
+		return "date"
+	case "dateTime", "timestamp":
+		return "datetime"
+	case "time":
+		return "time"
+	}
+	return ""
+}
+
+func (g *SqlServerGrammar) compileAutoIncrement(column *ColumnDefinition) string {
+	return " IDENTITY PRIMARY KEY"
+}
+
+func (g *SqlServerGrammar) compileAdd(table string, column *ColumnDefinition) string {
+	return "ALTER TABLE " + g.Wrap(table) + " ADD " + g.compileColumn(column)
+}
+
+func (g *SqlServerGrammar) compileRename(from string, to string) string {
+	return "EXEC sp_rename " + g.quote(from) + ", " + g.quote(to)
+}
+
+func (g *SqlServerGrammar) compileRenameColumn(table string, from string, to string) string {
+	return "EXEC sp_rename " + g.quote(table+"."+from) + ", " + g.quote(to) + ", 'COLUMN'"
+}
+
+func (g *SqlServerGrammar) compileDropIndex(table string, name string) string {
+	return "DROP INDEX " + g.Wrap(name) + " ON " + g.Wrap(table)
+}
+
+// quote Quote a name passed to a stored procedure as a string.
+func (g *SqlServerGrammar) quote(value string) string {
+	return "N'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/glugox/unogo/db/migration"
+	"github.com/glugox/unogo/db/schema"
 )
 
 func init() {
@@ -11,11 +12,14 @@ func init() {
 }
 func upcrateUsersTable(tx *sql.Tx) error {
 	// This code is executed when the migration is applied.
-	tx.Exec("CREATE TABLE users")
-	return nil
+	return schema.New(tx).Create("users", func(t *schema.Blueprint) {
+		t.ID()
+		t.String("name")
+		t.String("email").Unique()
+		t.Timestamps()
+	})
 }
 func downcrateUsersTable(tx *sql.Tx) error {
 	// This code is executed when the migration is rolled back.
-	tx.Exec("DROP TABLE users")
-	return nil
+	return schema.New(tx).DropIfExists("users")
 }
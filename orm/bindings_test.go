@@ -0,0 +1,127 @@
+package orm
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/glugox/unogo/orm/grammar"
+)
+
+// The clauses randomBuilder combines. Every clause binds the number n to a
+// column named cn, so the column in front of each place-holder tells which
+// binding it should get.
+var bindingClauses = []func(b *Builder, g grammar.Grammar, n int){
+	func(b *Builder, g grammar.Grammar, n int) { b.Where(column(n), n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.OrWhere(column(n), ">", n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.WhereIn(column(n), []int{n, n, n}) },
+	func(b *Builder, g grammar.Grammar, n int) { b.WhereBetween(column(n), []interface{}{n, n}) },
+	func(b *Builder, g grammar.Grammar, n int) { b.WhereRaw(column(n)+" <> ?", n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.SelectRaw(column(n)+" + ? AS s", n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.Join("j", column(n), "=", n, "inner", true) },
+	func(b *Builder, g grammar.Grammar, n int) { b.Having(column(n), ">", n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.OrderByRaw(column(n)+" = ?", n) },
+	func(b *Builder, g grammar.Grammar, n int) { b.GroupByRaw(column(n)+" + ?", n) },
+	func(b *Builder, g grammar.Grammar, n int) {
+		b.Where(func(q *Builder) { q.Where(column(n), n).OrWhere(column(n), n) })
+	},
+	func(b *Builder, g grammar.Grammar, n int) {
+		b.WhereIn(column(n), NewBuilder(nil, g).From("t").Select("id").Where(column(n), n))
+	},
+	func(b *Builder, g grammar.Grammar, n int) {
+		b.WhereExists(NewBuilder(nil, g).From("t").Where(column(n), n))
+	},
+	func(b *Builder, g grammar.Grammar, n int) {
+		b.SelectSub(NewBuilder(nil, g).From("t").SelectRaw("COUNT(*)").Where(column(n), n), "s")
+	},
+	func(b *Builder, g grammar.Grammar, n int) {
+		b.FromSub(NewBuilder(nil, g).From("t").Where(column(n), n), "t")
+	},
+}
+
+func column(n int) string {
+	return "c" + strconv.Itoa(n)
+}
+
+// randomBuilder Build a query out of random clauses, sometimes with a union
+// added between them.
+func randomBuilder(r *rand.Rand, g grammar.Grammar) *Builder {
+	b := NewBuilder(nil, g).From("t")
+	clauses := 1 + r.Intn(12)
+	union := -1
+	if r.Intn(3) == 0 {
+		union = 1 + r.Intn(clauses+1)
+	}
+	for n := 1; n <= clauses+1; n++ {
+		if n == union {
+			b.Union(NewBuilder(nil, g).From("t").Where(column(100), 100))
+		}
+		if n <= clauses {
+			bindingClauses[r.Intn(len(bindingClauses))](b, g, n)
+		}
+	}
+	return b
+}
+
+// checkBindings Check that every place-holder of the SQL gets the binding of
+// the column in front of it.
+func checkBindings(t *testing.T, sql string, bindings []interface{}) {
+	t.Helper()
+
+	placeholders := regexp.MustCompile(`\?|\$\d+`).FindAllStringIndex(sql, -1)
+	if len(placeholders) != len(bindings) {
+		t.Fatalf("invalid binding count: got:%d want:%d\nsql: %s\nbindings: %v", len(bindings), len(placeholders), sql, bindings)
+	}
+
+	columns := regexp.MustCompile(`c(\d+)`)
+	for i, placeholder := range placeholders {
+		if p := sql[placeholder[0]:placeholder[1]]; p != "?" && p != "$"+strconv.Itoa(i+1) {
+			t.Errorf("invalid place-holder %d: got:%s want:$%d", i, p, i+1)
+		}
+
+		matches := columns.FindAllStringSubmatch(sql[:placeholder[0]], -1)
+		want, _ := strconv.Atoi(matches[len(matches)-1][1])
+		if fmt.Sprint(bindings[i]) != strconv.Itoa(want) {
+			t.Fatalf("invalid binding %d: got:%v want:%d\nsql: %s\nbindings: %v", i, bindings[i], want, sql, bindings)
+		}
+	}
+}
+
+func TestBindingOrder(t *testing.T) {
+	grammars := []grammar.Grammar{grammar.NewMySqlGrammar(), grammar.NewSqliteGrammar(), grammar.NewPostgresGrammar()}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		g := grammars[i%len(grammars)]
+		b := randomBuilder(r, g)
+		checkBindings(t, b.ToSql(), b.GetBindings())
+	}
+}
+
+func TestUpdateBindingOrder(t *testing.T) {
+	grammars := []grammar.Grammar{grammar.NewMySqlGrammar(), grammar.NewSqliteGrammar(), grammar.NewPostgresGrammar()}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		g := grammars[i%len(grammars)]
+		b := NewBuilder(nil, g).From("t")
+		for n := 1; n <= 1+r.Intn(6); n++ {
+			switch r.Intn(4) {
+			case 0:
+				b.Join("j", column(n), "=", n, "inner", true)
+			case 1:
+				b.Where(column(n), n)
+			case 2:
+				b.WhereIn(column(n), []int{n, n})
+			case 3:
+				b.OrderByRaw(column(n)+" = ?", n)
+			}
+		}
+
+		values := map[string]interface{}{column(50): 50, column(51): 51, column(52): 52}
+		sql := g.CompileUpdate(b.Query, values)
+		checkBindings(t, sql, g.PrepareBindingsForUpdate(b.Bindings, values))
+	}
+}
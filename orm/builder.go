@@ -1,7 +1,10 @@
 package orm
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"errors"
@@ -15,6 +18,9 @@ type Builder struct {
 	grammar    grammar.Grammar
 	Query      *query.Query
 	Bindings   map[string][]interface{}
+	eagerLoad  []string
+	softDelete string
+	trashed    string
 }
 
 type Binding []interface{}
@@ -29,19 +35,41 @@ func NewBuilder(connection *Connection, grammar grammar.Grammar) *Builder {
 	}
 }
 
-// Select the columns to be selected.
-func (b *Builder) Select(columns ...string) *Builder {
+// WithContext Run the statements of the query with the given context.
+func (b *Builder) WithContext(ctx context.Context) *Builder {
+	b.Connection = b.Connection.withContext(ctx)
+	return b
+}
+
+// Select the columns to be selected. Columns are wrapped as identifiers and
+// may be aliased, "name as username"; a raw expression created by
+// query.Expr is selected as it is.
+func (b *Builder) Select(columns ...interface{}) *Builder {
 	if len(columns) == 0 {
-		columns = []string{"*"}
+		columns = []interface{}{"*"}
+	}
+	b.Query.Columns = nil
+
+	return b.AddSelect(columns...)
+}
+
+// SelectSub Add a subselect expression to the query.
+func (b *Builder) SelectSub(sub *Builder, as string) *Builder {
+	b.Query.Columns = append(b.Query.Columns, &query.Sub{
+		Query: sub.scopedQuery(),
+		As:    as,
+	})
+
+	if bindings := sub.GetBindings(); len(bindings) > 0 {
+		b.AddBinding(bindings, "select")
 	}
-	b.Query.Columns = columns
 
 	return b
 }
 
 // SelectRaw Add a new "raw" select expression to the query.
 func (b *Builder) SelectRaw(expression string, bindings ...interface{}) *Builder {
-	b.AddSelect(expression)
+	b.AddSelect(query.Expr(expression))
 
 	if len(bindings) != 0 {
 		b.AddBinding(bindings, "select")
@@ -50,7 +78,8 @@ func (b *Builder) SelectRaw(expression string, bindings ...interface{}) *Builder
 	return b
 }
 
-func (b *Builder) AddSelect(columns ...string) *Builder {
+// AddSelect Add new columns to be selected.
+func (b *Builder) AddSelect(columns ...interface{}) *Builder {
 	for _, column := range columns {
 		b.Query.Columns = append(b.Query.Columns, column)
 	}
@@ -61,14 +90,33 @@ func (b *Builder) AddSelect(columns ...string) *Builder {
 // From Set the table which the query is targeting.
 func (b *Builder) From(table string) *Builder {
 	b.Query.From = table
+	b.Query.FromSub = nil
 	return b
 }
 
-// Join Add a join clause to the query.
+// FromSub Makes "from" fetch from a subquery.
+func (b *Builder) FromSub(sub *Builder, as string) *Builder {
+	b.Query.From = as
+	b.Query.FromSub = &query.Sub{
+		Query: sub.scopedQuery(),
+		As:    as,
+	}
+
+	b.Bindings["from"] = nil
+	if bindings := sub.GetBindings(); len(bindings) > 0 {
+		b.AddBinding(bindings, "from")
+	}
+
+	return b
+}
+
+// Join Add a join clause to the query. The optional arguments are the
+// operator, the second column, the join type and whether the second argument
+// is a value to compare against rather than a column.
 func (b *Builder) Join(table string, first string, args ...interface{}) *Builder {
 	var (
 		operator string
-		second   string
+		second   interface{}
 		typtStr  string
 		where    bool
 	)
@@ -81,15 +129,15 @@ func (b *Builder) Join(table string, first string, args ...interface{}) *Builder
 		operator = args[0].(string)
 	}
 	if count >= 2 {
-		second = args[1].(string)
+		second = args[1]
 	}
 	if count >= 3 {
 		typtStr, _ = args[2].(string)
 	}
 	if count >= 4 {
-		where, _ = args[2].(bool)
+		where, _ = args[3].(bool)
 	}
-	q := &query.Query{}
+	q := &query.Query{JoinClause: true}
 
 	if where {
 		where := &query.Where{
@@ -97,6 +145,7 @@ func (b *Builder) Join(table string, first string, args ...interface{}) *Builder
 			Column:   first,
 			Operator: operator,
 			Value:    second,
+			Boolean:  "and",
 		}
 		q.Wheres = append(q.Wheres, where)
 		b.AddBinding(second, "join")
@@ -105,10 +154,9 @@ func (b *Builder) Join(table string, first string, args ...interface{}) *Builder
 			Type:     "Column",
 			First:    first,
 			Operator: operator,
-			Second:   second,
+			Second:   fmt.Sprint(second),
 			Boolean:  "and",
 		}
-		q.JoinClause = true
 		q.Wheres = append(q.Wheres, where)
 	}
 
@@ -120,16 +168,71 @@ func (b *Builder) Join(table string, first string, args ...interface{}) *Builder
 	return b
 }
 
-// func (b *Builder) Value(column string) error {
-// 	result, err := b.First([]string{column})
-// 	if len(result) > 0 {
-// 		return result[column], err
-// 	}
-// 	return nil, err
-// }
+// Value Get a single column's value from the first result of the query into
+// dest. dest is left untouched when there are no results.
+func (b *Builder) Value(column string, dest interface{}) error {
+	return b.CloneWithoutBindings("select").Select(column).Take(1).runSelect(dest)
+}
+
+// Pluck Get the values of a single column into dest, a pointer to a slice
+// such as *[]string.
+func (b *Builder) Pluck(column string, dest interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return errors.New("unsupported destination, should be pointer to slice")
+	}
+
+	return b.CloneWithoutBindings("select").Select(column).runSelect(dest)
+}
+
+// KeyBy Get the results of the query into dest, a pointer to a map of
+// structs, keyed by the value of the given column of every result.
+func (b *Builder) KeyBy(column string, dest interface{}, columns ...interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Map {
+		return errors.New("unsupported destination, should be pointer to map")
+	}
+
+	results := value.Elem()
+	keyType := results.Type().Key()
+
+	models := reflect.New(reflect.SliceOf(results.Type().Elem()))
+	if err := b.Get(models.Interface(), columns...); err != nil {
+		return err
+	}
+
+	if results.IsNil() {
+		results.Set(reflect.MakeMapWithSize(results.Type(), models.Elem().Len()))
+	}
+
+	alias := columnAlias(column)
+	for i := 0; i < models.Elem().Len(); i++ {
+		model := models.Elem().Index(i)
+
+		key, err := fieldValue(reflect.Indirect(model), alias)
+		if err != nil {
+			return err
+		}
+
+		k := reflect.ValueOf(key)
+		switch {
+		case k.Type().AssignableTo(keyType):
+		case keyType.Kind() == reflect.String:
+			k = reflect.ValueOf(fmt.Sprint(key)).Convert(keyType)
+		case k.Type().ConvertibleTo(keyType) && k.Kind() != reflect.String:
+			k = k.Convert(keyType)
+		default:
+			return fmt.Errorf("column [%s] of type %s can not key a map of %s", column, k.Type(), keyType)
+		}
+
+		results.SetMapIndex(k, model)
+	}
+
+	return nil
+}
 
 func (b *Builder) First(dest interface{}, columns ...interface{}) error {
-	return b.Take(1).Get(dest, columns...)
+	return b.Clone().Take(1).Get(dest, columns...)
 }
 
 func (b *Builder) Get(dest interface{}, columns ...interface{}) error {
@@ -148,15 +251,24 @@ func (b *Builder) Get(dest interface{}, columns ...interface{}) error {
 		cols = []string{"*"}
 	}
 
-	original := b.Query.Columns
-
-	if original == nil {
-		b.Query.Columns = cols
+	builder := b
+	if b.Query.Columns == nil {
+		builder = b.Clone().Select(interfaceSlice(cols)...)
 	}
 
-	err := b.runSelect(dest)
+	err := builder.runSelect(dest)
+
+	if err == nil && len(b.eagerLoad) > 0 {
+		err = b.eagerLoadRelations(dest)
+	}
 
-	b.Query.Columns = original
+	if err == nil {
+		for _, model := range collectModels(reflect.Indirect(reflect.ValueOf(dest))) {
+			if err = callHooks(model.Addr().Interface(), b.Connection, afterFind); err != nil {
+				break
+			}
+		}
+	}
 
 	return err
 }
@@ -165,8 +277,10 @@ func (b *Builder) Scan(dest ...interface{}) error {
 	return b.runScan(dest...)
 }
 
-// Where Add a basic where clause to the query.
-func (b *Builder) Where(column string, args ...interface{}) *Builder {
+// Where Add a basic where clause to the query. Passing a func(*Builder)
+// instead of a column adds the where clauses built by it as a nested,
+// parenthesized group.
+func (b *Builder) Where(column interface{}, args ...interface{}) *Builder {
 	var (
 		operator string
 		value    interface{}
@@ -177,6 +291,13 @@ func (b *Builder) Where(column string, args ...interface{}) *Builder {
 
 	count := len(args)
 
+	if callback, ok := column.(func(*Builder)); ok {
+		if count >= 1 {
+			boolean, _ = args[0].(string)
+		}
+		return b.whereNested(callback, boolean)
+	}
+
 	if count == 1 {
 		operator = "="
 		value = args[0]
@@ -189,22 +310,39 @@ func (b *Builder) Where(column string, args ...interface{}) *Builder {
 		boolean, _ = args[2].(string)
 	}
 
-	where := &query.Where{
-		Type:     "Basic",
-		Column:   column,
-		Operator: operator,
-		Value:    value,
-		Boolean:  boolean,
+	// Raw expressions, created by query.Expr, are added to the SQL as they
+	// are, both as the column and as the value.
+	placeholder := "?"
+	valueExpression, isExpression := value.(grammar.Expression)
+	if isExpression {
+		placeholder = fmt.Sprint(valueExpression.GetValue())
+	}
+
+	if expression, ok := column.(grammar.Expression); ok {
+		b.Query.Wheres = append(b.Query.Wheres, &query.Where{
+			Type:    "Raw",
+			Sql:     fmt.Sprintf("%v %s %s", expression.GetValue(), operator, placeholder),
+			Boolean: boolean,
+		})
+	} else {
+		b.Query.Wheres = append(b.Query.Wheres, &query.Where{
+			Type:     "Basic",
+			Column:   column.(string),
+			Operator: operator,
+			Value:    value,
+			Boolean:  boolean,
+		})
 	}
-	b.Query.Wheres = append(b.Query.Wheres, where)
 
-	b.AddBinding(value, "where")
+	if !isExpression {
+		b.AddBinding(value, "where")
+	}
 
 	return b
 }
 
 // OrWhere Add an "or where" clause to the query.
-func (b *Builder) OrWhere(column string, args ...interface{}) *Builder {
+func (b *Builder) OrWhere(column interface{}, args ...interface{}) *Builder {
 	var (
 		operator string
 		value    interface{}
@@ -212,6 +350,10 @@ func (b *Builder) OrWhere(column string, args ...interface{}) *Builder {
 
 	count := len(args)
 
+	if _, ok := column.(func(*Builder)); ok {
+		return b.Where(column, "OR")
+	}
+
 	if count == 1 {
 		operator = "="
 		value = args[0]
@@ -224,6 +366,41 @@ func (b *Builder) OrWhere(column string, args ...interface{}) *Builder {
 	return b.Where(column, operator, value, "OR")
 }
 
+// whereNested Add a nested where statement to the query.
+func (b *Builder) whereNested(callback func(*Builder), boolean string) *Builder {
+	nested := NewBuilder(b.Connection, b.grammar).From(b.Query.From)
+
+	callback(nested)
+
+	if len(nested.Query.Wheres) > 0 {
+		b.Query.Wheres = append(b.Query.Wheres, &query.Where{
+			Type:    "Nested",
+			Query:   nested.Query,
+			Boolean: boolean,
+		})
+		b.AddBinding(nested.Bindings["where"], "where")
+	}
+
+	return b
+}
+
+// groupWheres Get the where clauses with any clause joined with "or" grouped
+// together, so that further constraints apply to all of them. The given
+// slice is never modified.
+func groupWheres(wheres []*query.Where) []*query.Where {
+	for _, where := range wheres {
+		if strings.EqualFold(where.Boolean, "or") {
+			return []*query.Where{{
+				Type:    "Nested",
+				Query:   &query.Query{Wheres: wheres},
+				Boolean: "and",
+			}}
+		}
+	}
+
+	return append([]*query.Where{}, wheres...)
+}
+
 // WhereColumn Add a "where" clause comparing two columns to the query.
 func (b *Builder) WhereColumn(first string, args ...interface{}) *Builder {
 	var (
@@ -316,8 +493,9 @@ func (b *Builder) OrWhereRaw(sql string, bindings ...interface{}) *Builder {
 	return b
 }
 
-// WhereIn Add a "where in" clause to the query.
-func (b *Builder) WhereIn(column string, values []interface{}, args ...interface{}) *Builder {
+// WhereIn Add a "where in" clause to the query. The values are either a
+// slice or a *Builder whose results the column is matched against.
+func (b *Builder) WhereIn(column string, values interface{}, args ...interface{}) *Builder {
 	var (
 		boolean string
 		not     bool
@@ -336,20 +514,39 @@ func (b *Builder) WhereIn(column string, values []interface{}, args ...interface
 		boolean = args[0].(string)
 		not = args[1].(bool)
 	}
+
+	if sub, ok := values.(*Builder); ok {
+		b.Query.Wheres = append(
+			b.Query.Wheres,
+			&query.Where{
+				Type:    "InSub",
+				Column:  column,
+				Query:   sub.scopedQuery(),
+				Boolean: boolean,
+				Not:     not,
+			},
+		)
+		if bindings := sub.GetBindings(); len(bindings) > 0 {
+			b.AddBinding(bindings, "where")
+		}
+		return b
+	}
+
 	t := "In"
 	if not {
 		t = "NotIn"
 	}
+	list := interfaceSlice(values)
 	b.Query.Wheres = append(
 		b.Query.Wheres,
 		&query.Where{
 			Type:    t,
 			Column:  column,
-			Values:  values,
+			Values:  list,
 			Boolean: boolean,
 		},
 	)
-	for _, value := range values {
+	for _, value := range list {
 		b.AddBinding(value, "where")
 	}
 
@@ -357,12 +554,12 @@ func (b *Builder) WhereIn(column string, values []interface{}, args ...interface
 }
 
 // OrWhereIn Add an "or where in" clause to the query.
-func (b *Builder) OrWhereIn(column string, values []interface{}) *Builder {
+func (b *Builder) OrWhereIn(column string, values interface{}) *Builder {
 	return b.WhereIn(column, values, "OR")
 }
 
 // WhereNotIn Add a "where not in" clause to the query.
-func (b *Builder) WhereNotIn(column string, values []interface{}, args ...interface{}) *Builder {
+func (b *Builder) WhereNotIn(column string, values interface{}, args ...interface{}) *Builder {
 	var boolean string
 	count := len(args)
 
@@ -376,10 +573,62 @@ func (b *Builder) WhereNotIn(column string, values []interface{}, args ...interf
 }
 
 // OrWhereNotIn Add an "or where not in" clause to the query.
-func (b *Builder) OrWhereNotIn(column string, values []interface{}) *Builder {
+func (b *Builder) OrWhereNotIn(column string, values interface{}) *Builder {
 	return b.WhereNotIn(column, values, "OR")
 }
 
+// WhereExists Add an exists clause to the query.
+func (b *Builder) WhereExists(sub *Builder, args ...interface{}) *Builder {
+	var (
+		boolean string
+		not     bool
+	)
+	count := len(args)
+
+	boolean = "and"
+
+	if count >= 1 {
+		boolean = args[0].(string)
+	}
+	if count >= 2 {
+		not = args[1].(bool)
+	}
+
+	b.Query.Wheres = append(
+		b.Query.Wheres,
+		&query.Where{
+			Type:    "Exists",
+			Query:   sub.scopedQuery(),
+			Boolean: boolean,
+			Not:     not,
+		},
+	)
+	if bindings := sub.GetBindings(); len(bindings) > 0 {
+		b.AddBinding(bindings, "where")
+	}
+
+	return b
+}
+
+// OrWhereExists Add an or exists clause to the query.
+func (b *Builder) OrWhereExists(sub *Builder) *Builder {
+	return b.WhereExists(sub, "OR")
+}
+
+// WhereNotExists Add a where not exists clause to the query.
+func (b *Builder) WhereNotExists(sub *Builder, args ...interface{}) *Builder {
+	boolean := "and"
+	if len(args) > 0 {
+		boolean = args[0].(string)
+	}
+	return b.WhereExists(sub, boolean, true)
+}
+
+// OrWhereNotExists Add a where not exists clause to the query.
+func (b *Builder) OrWhereNotExists(sub *Builder) *Builder {
+	return b.WhereNotExists(sub, "OR")
+}
+
 // WhereNull Add a "where null" clause to the query.
 func (b *Builder) WhereNull(column string, args ...interface{}) *Builder {
 	var (
@@ -502,7 +751,7 @@ func (b *Builder) OrWhereNotBetween(column string, values []interface{}) *Builde
 }
 
 // GroupBy Add a "group by" clause to the query.
-func (b *Builder) GroupBy(groups ...string) *Builder {
+func (b *Builder) GroupBy(groups ...interface{}) *Builder {
 	if len(groups) != 0 {
 		for _, group := range groups {
 			b.Query.Groups = append(b.Query.Groups, group)
@@ -512,6 +761,17 @@ func (b *Builder) GroupBy(groups ...string) *Builder {
 	return b
 }
 
+// GroupByRaw Add a raw "group by" clause to the query.
+func (b *Builder) GroupByRaw(sql string, bindings ...interface{}) *Builder {
+	b.Query.Groups = append(b.Query.Groups, query.Expr(sql))
+
+	if len(bindings) != 0 {
+		b.AddBinding(bindings, "group")
+	}
+
+	return b
+}
+
 // Having Add a "having" clause to the query.
 func (b *Builder) Having(column string, args ...interface{}) *Builder {
 	var (
@@ -550,6 +810,31 @@ func (b *Builder) Having(column string, args ...interface{}) *Builder {
 	return b
 }
 
+// Union Add a union statement to the query.
+func (b *Builder) Union(other *Builder, args ...interface{}) *Builder {
+	var all bool
+
+	if len(args) > 0 {
+		all, _ = args[0].(bool)
+	}
+
+	b.Query.Unions = append(b.Query.Unions, &query.Union{
+		Query: other.scopedQuery(),
+		All:   all,
+	})
+
+	if bindings := other.GetBindings(); len(bindings) > 0 {
+		b.AddBinding(bindings, "union")
+	}
+
+	return b
+}
+
+// UnionAll Add a union all statement to the query.
+func (b *Builder) UnionAll(other *Builder) *Builder {
+	return b.Union(other, true)
+}
+
 // OrderBy Add an "order by" clause to the query.
 func (b *Builder) OrderBy(column string, args ...string) *Builder {
 	direction := "asc"
@@ -604,7 +889,11 @@ func (b *Builder) OrderByRaw(sql string, bindings ...interface{}) *Builder {
 	}
 
 	if len(bindings) != 0 {
-		b.AddBinding(bindings, "order")
+		if len(b.Query.Unions) == 0 {
+			b.AddBinding(bindings, "order")
+		} else {
+			b.AddBinding(bindings, "unionOrder")
+		}
 	}
 
 	return b
@@ -644,6 +933,37 @@ func (b *Builder) Offset(value uint64) *Builder {
 	return b
 }
 
+// UseWriter Run the select statements of the query on the writer rather
+// than on a reader. Locking queries always do.
+func (b *Builder) UseWriter() *Builder {
+	if b.Connection != nil {
+		b.Connection = b.Connection.UseWriter()
+	}
+	return b
+}
+
+// LockForUpdate Lock the selected rows in the table for updating. The
+// query.NoWait or query.SkipLocked option changes how locked rows are handled.
+func (b *Builder) LockForUpdate(options ...string) *Builder {
+	return b.lock(false, options)
+}
+
+// SharedLock Share lock the selected rows in the table. The query.NoWait or
+// query.SkipLocked option changes how locked rows are handled.
+func (b *Builder) SharedLock(options ...string) *Builder {
+	return b.lock(true, options)
+}
+
+func (b *Builder) lock(shared bool, options []string) *Builder {
+	b.UseWriter()
+	b.Query.Lock = &query.Lock{
+		Shared: shared,
+		Option: strings.Join(options, " "),
+	}
+
+	return b
+}
+
 // Count Retrieve the "count" result of the query.
 func (b *Builder) Count(dest ...interface{}) error {
 	return b.Aggregate("COUNT", []string{"*"}, dest...)
@@ -677,19 +997,20 @@ func (b *Builder) Aggregate(function string, columns []string, dest ...interface
 		cols = columns
 	}
 
-	nb := b.CloneWithout("columns").CloneWithoutBindings("select").setAggregate(function, cols)
-
-	original := nb.Query.Columns
-
-	if original == nil {
-		nb.Query.Columns = cols
+	// A union query is aggregated over its results, so the columns of its
+	// first select have to stay in line with those of the unions.
+	if len(b.Query.Unions) > 0 {
+		return b.Clone().setAggregate(function, cols).Scan(dest...)
 	}
 
-	return nb.Scan(dest...)
+	return b.CloneWithout("columns").CloneWithoutBindings("select").
+		setAggregate(function, cols).
+		Select(interfaceSlice(cols)...).
+		Scan(dest...)
 }
 
 func (b *Builder) ToSql() string {
-	return b.grammar.CompileSelect(b.Query)
+	return b.grammar.CompileSelect(b.scopedQuery())
 }
 
 // AddBinding Add a binding to the query.
@@ -697,8 +1018,7 @@ func (b *Builder) AddBinding(value interface{}, segment string) {
 	if _, ok := b.Bindings[segment]; !ok {
 		return
 	}
-	t := reflect.TypeOf(value)
-	switch t.Kind() {
+	switch reflect.ValueOf(value).Kind() {
 	case reflect.Slice:
 		s := reflect.ValueOf(value)
 		for i := 0; i < s.Len(); i++ {
@@ -709,29 +1029,61 @@ func (b *Builder) AddBinding(value interface{}, segment string) {
 	}
 }
 
+// GetBindings Get the current query value bindings, in the order their
+// place-holders appear in the compiled SQL.
 func (b *Builder) GetBindings() []interface{} {
 	var bindings []interface{}
-	for _, val := range b.Bindings {
-		for _, v := range val {
+	for _, segment := range bindingSegments {
+		for _, v := range b.Bindings[segment] {
 			bindings = append(bindings, v)
 		}
 	}
 	return bindings
 }
 
+// Clone Get a copy of the builder with a deep copy of its query and
+// bindings, so that building on either one leaves the other untouched.
+func (b *Builder) Clone() *Builder {
+	builder := *b
+	builder.Query = b.Query.Clone()
+	builder.eagerLoad = append([]string(nil), b.eagerLoad...)
+
+	builder.Bindings = make(map[string][]interface{}, len(b.Bindings))
+	for segment, bindings := range b.Bindings {
+		builder.Bindings[segment] = append(make([]interface{}, 0, len(bindings)), bindings...)
+	}
+
+	return &builder
+}
+
 // CloneWithout Clone the query without the given properties.
 func (b *Builder) CloneWithout(properties ...string) *Builder {
-	builder := b
+	builder := b.Clone()
 	for _, property := range properties {
 		switch property {
 		case "columns":
 			builder.Query.Columns = nil
+		case "joins":
+			builder.Query.Joins = nil
+		case "wheres":
+			builder.Query.Wheres = nil
+		case "groups":
+			builder.Query.Groups = nil
+		case "havings":
+			builder.Query.Havings = nil
 		case "orders":
-			// builder.Query.Columns = nil
+			builder.Query.Orders = nil
+			builder.Query.UnionOrders = nil
 		case "limit":
 			builder.Query.Limit = 0
+			builder.Query.UnionLimit = 0
 		case "offset":
-			// builder.Query.Columns = nil
+			builder.Query.Offset = 0
+			builder.Query.UnionOffset = 0
+		case "unions":
+			builder.Query.Unions = nil
+		case "lock":
+			builder.Query.Lock = nil
 		}
 	}
 	return builder
@@ -739,7 +1091,7 @@ func (b *Builder) CloneWithout(properties ...string) *Builder {
 
 // CloneWithoutBindings Clone the query without the given bindings.
 func (b *Builder) CloneWithoutBindings(except ...string) *Builder {
-	builder := b
+	builder := b.Clone()
 	for _, val := range except {
 		builder.Bindings[val] = nil
 	}
@@ -793,9 +1145,56 @@ func (b *Builder) Inserts(values []map[string]interface{}) (int64, int64, error)
 	return b.Connection.Insert(sql, bindings...)
 }
 
+// InsertOrIgnore Insert new records into the database, skipping the ones
+// that conflict with an existing record. It returns the number of inserted rows.
+func (b *Builder) InsertOrIgnore(values ...map[string]interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	sql, bindings := b.GetGrammar().CompileInsertOrIgnore(b.Query, values)
+	_, affected, err := b.Connection.Insert(sql, bindings...)
+	return affected, err
+}
+
+// Upsert Insert new records or update the existing ones. Records conflicting
+// on the uniqueBy columns get the update columns set to the new values. A nil
+// update updates every inserted column, an empty one makes it InsertOrIgnore.
+func (b *Builder) Upsert(values []map[string]interface{}, uniqueBy []string, update []string) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	if update == nil {
+		for column := range values[0] {
+			update = append(update, column)
+		}
+		sort.Strings(update)
+	}
+
+	if len(update) == 0 {
+		return b.InsertOrIgnore(values...)
+	}
+
+	sql, bindings := b.GetGrammar().CompileUpsert(b.Query, values, uniqueBy, update)
+	_, affected, err := b.Connection.Insert(sql, bindings...)
+	return affected, err
+}
+
+// InsertGetId Insert a new record and get the value of the primary key.
+func (b *Builder) InsertGetId(value map[string]interface{}, sequence ...string) (int64, error) {
+	column := "id"
+	if len(sequence) > 0 {
+		column = sequence[0]
+	}
+
+	sql, bindings := b.GetGrammar().CompileInsertGetId(b.Query, value, column)
+	return b.Connection.InsertGetId(sql, bindings...)
+}
+
 // Update a record in the database.
 func (b *Builder) Update(value map[string]interface{}) (int64, error) {
-	sql := b.GetGrammar().CompileUpdate(b.Query, value)
+	sql := b.GetGrammar().CompileUpdate(b.scopedQuery(), value)
 	cleanBindings := cleanBindings(b.GetGrammar().PrepareBindingsForUpdate(b.Bindings, value))
 	return b.Connection.Update(
 		sql,
@@ -803,14 +1202,19 @@ func (b *Builder) Update(value map[string]interface{}) (int64, error) {
 	)
 }
 
-// Delete a record from the database.
+// Delete a record from the database. Records of models using soft deletes
+// only get their deleted_at column set, see ForceDelete.
 func (b *Builder) Delete(args ...interface{}) (int64, error) {
 	if len(args) > 0 {
 		b.Where(b.Query.From+".id", args[0])
 	}
 
+	if b.softDelete != "" {
+		return b.Update(b.softDeleteValues())
+	}
+
 	return b.Connection.Delete(
-		b.GetGrammar().CompileDelete(b.Query),
+		b.GetGrammar().CompileDelete(b.scopedQuery()),
 		b.GetBindings()...,
 	)
 }
@@ -826,13 +1230,37 @@ func cleanBindings(bindings []interface{}) []interface{} {
 	return result
 }
 
+// bindingSegments The binding segments in the order of the SQL components.
+// The orders added after a union are compiled after the unions, so their
+// bindings come last.
+var bindingSegments = []string{"select", "from", "join", "where", "group", "having", "order", "union", "unionOrder"}
+
+// interfaceSlice Convert a slice of any element type to []interface{}.
+func interfaceSlice(values interface{}) []interface{} {
+	if list, ok := values.([]interface{}); ok {
+		return list
+	}
+
+	var list []interface{}
+	v := reflect.ValueOf(values)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, v.Index(i).Interface())
+		}
+	}
+	return list
+}
+
 func getDefaultBindings() map[string][]interface{} {
 	return map[string][]interface{}{
-		"select": make([]interface{}, 0),
-		"join":   make([]interface{}, 0),
-		"where":  make([]interface{}, 0),
-		"having": make([]interface{}, 0),
-		"order":  make([]interface{}, 0),
-		"union":  make([]interface{}, 0),
+		"select":     make([]interface{}, 0),
+		"from":       make([]interface{}, 0),
+		"join":       make([]interface{}, 0),
+		"where":      make([]interface{}, 0),
+		"group":      make([]interface{}, 0),
+		"having":     make([]interface{}, 0),
+		"order":      make([]interface{}, 0),
+		"union":      make([]interface{}, 0),
+		"unionOrder": make([]interface{}, 0),
 	}
 }
@@ -0,0 +1,388 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/glugox/unogo/orm/grammar"
+)
+
+func insertUsers(t *testing.T, conn *Connection, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		if err := conn.Create(&testUser{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan")
+
+	var users []testUser
+	err := conn.Table("users").
+		Where("name", "<", "bob").
+		UnionAll(conn.Table("users").Where("name", "dan")).
+		Union(conn.Table("users").Where("name", "dan")).
+		OrderByDesc("name").
+		Limit(3).
+		Get(&users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"dan", "ana"}
+	if len(users) != len(want) {
+		t.Fatalf("invalid union count: got:%d want:%d", len(users), len(want))
+	}
+	for i, user := range users {
+		if user.Name != want[i] {
+			t.Errorf("invalid union row %d: got:%s want:%s", i, user.Name, want[i])
+		}
+	}
+
+	var count int
+	err = conn.Table("users").UnionAll(conn.Table("users")).Count(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 8 {
+		t.Errorf("invalid union count: got:%d want:%d", count, 8)
+	}
+
+	err = conn.Table("users").Select("id").SelectRaw("? AS one", 1).
+		Union(conn.Table("users").Select("id").SelectRaw("? AS one", 1).Where("name", "ana")).
+		Count(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Errorf("invalid selected union count: got:%d want:%d", count, 4)
+	}
+}
+
+func TestNestedWheresAndSubqueries(t *testing.T) {
+	b := NewBuilder(nil, grammar.NewMySqlGrammar()).From("users").
+		SelectSub(NewBuilder(nil, grammar.NewMySqlGrammar()).From("posts").SelectRaw("COUNT(*)").WhereColumn("posts.user_id", "users.id").Where("posts.draft", false), "post_count").
+		Where(func(q *Builder) {
+			q.Where("name", "ana").OrWhere("name", "bob")
+		}).
+		Where("active", true).
+		WhereIn("id", NewBuilder(nil, grammar.NewMySqlGrammar()).From("admins").Select("user_id").Where("level", ">", 2)).
+		WhereNotExists(NewBuilder(nil, grammar.NewMySqlGrammar()).From("bans").WhereColumn("bans.user_id", "users.id").Where("bans.until", ">", "now"))
+
+	want := "SELECT (SELECT COUNT(*) FROM `posts` WHERE `posts`.`user_id` = `users`.`id` and `posts`.`draft` = ?) AS `post_count` " +
+		"FROM `users` WHERE (`name` = ? OR `name` = ?) and `active` = ? " +
+		"and `id` IN (SELECT `user_id` FROM `admins` WHERE `level` > ?) " +
+		"and NOT EXISTS (SELECT * FROM `bans` WHERE `bans`.`user_id` = `users`.`id` and `bans`.`until` > ?)"
+	if got := b.ToSql(); got != want {
+		t.Errorf("invalid sql:\ngot:  %s\nwant: %s", got, want)
+	}
+
+	bindings := b.GetBindings()
+	wantBindings := []interface{}{false, "ana", "bob", true, 2, "now"}
+	if len(bindings) != len(wantBindings) {
+		t.Fatalf("invalid bindings: got:%v want:%v", bindings, wantBindings)
+	}
+	for i := range bindings {
+		if bindings[i] != wantBindings[i] {
+			t.Errorf("invalid binding %d: got:%v want:%v", i, bindings[i], wantBindings[i])
+		}
+	}
+}
+
+func TestSubqueriesAgainstDatabase(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan")
+
+	var users []testUser
+	err := conn.Table("users").
+		Where(func(q *Builder) {
+			q.Where("name", "ana").OrWhere("name", "dan")
+		}).
+		WhereIn("id", conn.Table("users").Select("id").Where("id", ">", 1)).
+		Get(&users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "dan" {
+		t.Errorf("invalid users: got:%+v", users)
+	}
+
+	var count int
+	err = conn.Query().FromSub(conn.Table("users").Where("name", "<", "cid"), "early").Count(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("invalid count: got:%d want:%d", count, 2)
+	}
+
+	users = nil
+	err = conn.Table("users").
+		WhereExists(conn.Table("users as u").WhereColumn("u.id", "<", "users.id").Where("u.name", "cid")).
+		Get(&users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "dan" {
+		t.Errorf("invalid users: got:%+v", users)
+	}
+}
+
+func TestInsertId(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana")
+
+	id, affected, err := conn.Table("users").Insert(map[string]interface{}{"name": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 || affected != 1 {
+		t.Errorf("invalid insert: got:%d,%d want:%d,%d", id, affected, 2, 1)
+	}
+
+	id, err = conn.Table("users").InsertGetId(map[string]interface{}{"name": "cid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 {
+		t.Errorf("invalid insert get id: got:%d want:%d", id, 3)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana")
+
+	_, err := conn.Table("users").Upsert([]map[string]interface{}{
+		{"id": 1, "name": "bob"},
+		{"id": 2, "name": "cid"},
+	}, []string{"id"}, []string{"name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	affected, err := conn.Table("users").InsertOrIgnore(map[string]interface{}{"id": 2, "name": "dan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 0 {
+		t.Errorf("invalid insert ignore affected rows: got:%d want:%d", affected, 0)
+	}
+
+	var users []testUser
+	if err := conn.Table("users").OrderBy("id").Get(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"bob", "cid"}
+	if len(users) != len(want) {
+		t.Fatalf("invalid upsert count: got:%d want:%d", len(users), len(want))
+	}
+	for i, user := range users {
+		if user.Name != want[i] {
+			t.Errorf("invalid upsert row %d: got:%s want:%s", i, user.Name, want[i])
+		}
+	}
+}
+
+func TestTablePrefixAgainstDatabase(t *testing.T) {
+	conn := newTestConnection(t)
+	conn.SetTablePrefix("app_")
+
+	if err := conn.Statement("CREATE TABLE app_users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255))"); err != nil {
+		t.Fatal(err)
+	}
+
+	insertUsers(t, conn, "ana", "bob")
+	if _, err := conn.Table("users").Where("users.name", "ana").Update(map[string]interface{}{"name": "cid"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Table("users").Where("name", "bob").Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	var users []testUser
+	if err := conn.Table("users").Select("users.id", "users.name").OrderBy("users.id").Get(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "cid" {
+		t.Errorf("invalid prefixed users: got:%v", users)
+	}
+
+	var name string
+	if err := conn.Query().FromSub(conn.Table("users"), "s").Where("s.id", users[0].ID).Value("s.name", &name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "cid" {
+		t.Errorf("invalid prefixed from sub name: got:%s want:%s", name, "cid")
+	}
+
+	conn.SetTablePrefix("")
+	if count := countUsers(t, conn); count != 0 {
+		t.Errorf("invalid unprefixed count: got:%d want:%d", count, 0)
+	}
+}
+
+func TestClone(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan")
+
+	base := conn.Table("users").Select("id", "name").Where("name", "<>", "cid").OrderByDesc("name")
+	want := base.ToSql()
+
+	clone := base.Clone().Where("name", "<>", "dan").Limit(1)
+	if got := base.ToSql(); got != want {
+		t.Errorf("invalid sql after clone:\ngot:  %s\nwant: %s", got, want)
+	}
+	if got := len(base.GetBindings()); got != 1 {
+		t.Errorf("invalid bindings after clone: got:%d want:%d", got, 1)
+	}
+
+	var users []testUser
+	if err := clone.Get(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "bob" {
+		t.Errorf("invalid clone users: got:%+v", users)
+	}
+
+	var count int
+	if err := base.Count(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("invalid count: got:%d want:%d", count, 3)
+	}
+
+	if _, err := base.Paginate(2, 1, &users); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.First(&users); err != nil {
+		t.Fatal(err)
+	}
+	if got := base.ToSql(); got != want {
+		t.Errorf("invalid sql after reuse:\ngot:  %s\nwant: %s", got, want)
+	}
+
+	users = nil
+	if err := base.Get(&users); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"dan", "bob", "ana"}
+	if len(users) != len(names) {
+		t.Fatalf("invalid users after reuse: got:%+v", users)
+	}
+	for i, user := range users {
+		if user.Name != names[i] {
+			t.Errorf("invalid user %d: got:%s want:%s", i, user.Name, names[i])
+		}
+	}
+}
+
+func TestPluckAndValue(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid")
+
+	var names []string
+	if err := conn.Table("users").OrderByDesc("id").Pluck("users.name", &names); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cid", "bob", "ana"}
+	if len(names) != len(want) {
+		t.Fatalf("invalid pluck: got:%v want:%v", names, want)
+	}
+	for i := range names {
+		if names[i] != want[i] {
+			t.Errorf("invalid pluck %d: got:%s want:%s", i, names[i], want[i])
+		}
+	}
+
+	var ids []*int64
+	if err := conn.Table("users").Where("name", "<>", "bob").Pluck("id", &ids); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || *ids[0] != 1 || *ids[1] != 3 {
+		t.Errorf("invalid pluck ids: got:%v", ids)
+	}
+
+	var name string
+	if err := conn.Table("users").Where("id", 2).Value("name", &name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "bob" {
+		t.Errorf("invalid value: got:%s want:%s", name, "bob")
+	}
+
+	// The bound columns are replaced, and their bindings with them.
+	selected := conn.Table("users").SelectRaw("id + ? AS next", 1).Where("name", "cid")
+	if err := selected.Value("name", &name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "cid" {
+		t.Errorf("invalid value of bound select: got:%s want:%s", name, "cid")
+	}
+	names = nil
+	if err := selected.Pluck("name", &names); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "cid" {
+		t.Errorf("invalid pluck of bound select: got:%v want:%v", names, []string{"cid"})
+	}
+
+	name = "none"
+	if err := conn.Table("users").Where("id", 9).Value("name", &name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "none" {
+		t.Errorf("invalid missing value: got:%s want:%s", name, "none")
+	}
+}
+
+func TestScanIntoMaps(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob")
+
+	var rows []map[string]interface{}
+	if err := conn.Table("users").OrderBy("id").Get(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("invalid rows: got:%v", rows)
+	}
+	if rows[1]["id"] != int64(2) || rows[1]["name"] != "bob" {
+		t.Errorf("invalid row: got:%v", rows[1])
+	}
+
+	var row map[string]interface{}
+	err := conn.Select("SELECT COUNT(*) AS total, MAX(name) AS last FROM users", nil, &row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row["total"] != int64(2) || row["last"] != "bob" {
+		t.Errorf("invalid row: got:%v", row)
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob")
+
+	var byId map[int]testUser
+	if err := conn.Table("users").KeyBy("id", &byId); err != nil {
+		t.Fatal(err)
+	}
+	if len(byId) != 2 || byId[1].Name != "ana" || byId[2].Name != "bob" {
+		t.Errorf("invalid users by id: got:%v", byId)
+	}
+
+	byName := make(map[string]*testUser)
+	if err := conn.Table("users").KeyBy("users.name", &byName); err != nil {
+		t.Fatal(err)
+	}
+	if len(byName) != 2 || byName["bob"] == nil || byName["bob"].ID != 2 {
+		t.Errorf("invalid users by name: got:%v", byName)
+	}
+}
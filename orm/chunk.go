@@ -0,0 +1,125 @@
+package orm
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Chunk Get the results of the query in batches of the given size. Every
+// batch is read into dest, a pointer to a slice, which is then passed to the
+// callback. Returning an error from the callback stops the chunking.
+//
+// Rows inserted or deleted while chunking shift the pages, ChunkById should
+// be used when the callback changes the queried rows.
+func (b *Builder) Chunk(size uint64, dest interface{}, callback func(batch interface{}) error) error {
+	if size == 0 {
+		return errors.New("chunk size should be greater than zero")
+	}
+
+	for page := uint64(0); ; page++ {
+		count, err := b.Clone().Limit(size).Offset(b.Query.Offset + page*size).getBatch(dest)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+
+		if err := callback(dest); err != nil {
+			return err
+		}
+
+		if uint64(count) < size {
+			return nil
+		}
+	}
+}
+
+// ChunkById Get the results of the query in batches of the given size, paging
+// by the given column, or by "id" if none is given, rather than by offset.
+// The column should be unique; it is read back from the last model of every
+// batch, so it should be selected as well.
+func (b *Builder) ChunkById(size uint64, dest interface{}, callback func(batch interface{}) error, column ...string) error {
+	if size == 0 {
+		return errors.New("chunk size should be greater than zero")
+	}
+
+	key := "id"
+	if len(column) > 0 {
+		key = column[0]
+	}
+	alias := columnAlias(key)
+
+	var last interface{}
+
+	for {
+		builder := b.CloneWithout("orders").CloneWithoutBindings("order", "unionOrder")
+		builder.Query.Wheres = groupWheres(builder.Query.Wheres)
+		if last != nil {
+			builder.Where(key, ">", last)
+		}
+
+		count, err := builder.OrderBy(key).Limit(size).getBatch(dest)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+
+		if last, err = lastValue(dest, alias); err != nil {
+			return err
+		}
+
+		if err := callback(dest); err != nil {
+			return err
+		}
+
+		if uint64(count) < size {
+			return nil
+		}
+	}
+}
+
+// getBatch Empty the slice dest points to and read the next batch into it,
+// returning the number of rows read.
+func (b *Builder) getBatch(dest interface{}) (int, error) {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return 0, errors.New("unsupported destination, should be pointer to slice")
+	}
+
+	slice := value.Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, slice.Cap()))
+
+	if err := b.Get(dest); err != nil {
+		return 0, err
+	}
+
+	return slice.Len(), nil
+}
+
+// lastValue Get the value of a column of the last model read into dest.
+func lastValue(dest interface{}, column string) (interface{}, error) {
+	models := collectModels(reflect.Indirect(reflect.ValueOf(dest)))
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	return fieldValue(models[len(models)-1], column)
+}
+
+// fieldValue Get the value of a column of a model.
+func fieldValue(model reflect.Value, column string) (interface{}, error) {
+	schema, err := NewSchema(model.Addr().Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := schema.Field(column)
+	if !ok || isNilValue(field.Value) {
+		return nil, errors.New("column [" + column + "] not found in the results")
+	}
+
+	return field.Value.Interface(), nil
+}
@@ -1,17 +1,82 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"math/rand"
 	"reflect"
+	"sync/atomic"
+	"time"
 
 	"github.com/glugox/unogo/orm/grammar"
 )
 
 type Connection struct {
-	DB          *sql.DB
-	tablePrefix string
+	// DB The database written to, and read from when there are no readers.
+	DB *sql.DB
+
+	readers     []*sql.DB
+	sticky      bool
+	modified    *int32
+	forceWriter bool
+
+	grammar      grammar.Grammar
+	tablePrefix  string
+	tx           *sql.Tx
+	transactions int
+	ctx          context.Context
+	listeners    *listeners
+}
+
+// executor The methods shared by *sql.DB and *sql.Tx that statements run on.
+type executor interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// WithContext Get a copy of the connection that runs its statements with the
+// given context, so they are cancelled together with it. With the Sticky
+// option the copy tracks its own writes, so a copy made for every request
+// reads the writes of that request.
+func (c *Connection) WithContext(ctx context.Context) *Connection {
+	conn := c.withContext(ctx)
+	conn.modified = new(int32)
+	return conn
+}
+
+func (c *Connection) withContext(ctx context.Context) *Connection {
+	conn := *c
+	conn.ctx = ctx
+	return &conn
+}
+
+// UseWriter Get a copy of the connection that also runs select statements
+// on the writer.
+func (c *Connection) UseWriter() *Connection {
+	conn := *c
+	conn.forceWriter = true
+	return &conn
+}
+
+// Close Close the writer and reader databases of the connection.
+func (c *Connection) Close() error {
+	err := c.DB.Close()
+	for _, reader := range c.readers {
+		if rerr := reader.Close(); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// Context Get the context statements of the connection run with.
+func (c *Connection) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // Table Begin a fluent query against a database table.
@@ -31,19 +96,22 @@ func (c *Connection) SelectOne(query string, bindings []interface{}, dest interf
 
 // Select Run a select statement against the database.
 func (c *Connection) Select(query string, bindings []interface{}, dest interface{}) error {
+	_, err := c.run(query, bindings, func() (int64, error) {
+		return 0, c.selectRows(query, bindings, dest)
+	})
+	return err
+}
 
-	log.Println(query)
-
-	var err error
-
-	stmt, err := c.DB.Prepare(query)
+// selectRows Read the rows of a select statement into dest.
+func (c *Connection) selectRows(query string, bindings []interface{}, dest interface{}) error {
+	stmt, err := c.readExecutor().PrepareContext(c.Context(), query)
 
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(bindings...)
+	rows, err := stmt.QueryContext(c.Context(), bindings...)
 	if err != nil {
 		return err
 	}
@@ -54,142 +122,232 @@ func (c *Connection) Select(query string, bindings []interface{}, dest interface
 		return err
 	}
 
-	results := reflect.Indirect(reflect.ValueOf(dest))
-
-	kind := results.Kind()
-	if kind != reflect.Slice && kind != reflect.Struct {
-		return errors.New("unsupported destination, should be slice or struct")
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("unsupported destination, should be pointer")
 	}
 
-	var isPtr bool
-	var resultType reflect.Type
-	var resultValue reflect.Value
-
-	resultType = results.Type()
-	resultValue = results
+	results := value.Elem()
 
-	if kind == reflect.Slice {
-		resultType = resultType.Elem()
+	// A slice gets every row, anything else only the first one. Byte
+	// slices are single values rather than lists.
+	if results.Kind() != reflect.Slice || results.Type().Elem().Kind() == reflect.Uint8 {
+		if rows.Next() {
+			if err := c.scanRow(rows, columns, results); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
 	}
 
+	var isPtr bool
+	resultType := results.Type().Elem()
+
 	if resultType.Kind() == reflect.Ptr {
 		resultType = resultType.Elem()
 		isPtr = true
 	}
 
-	if kind == reflect.Slice {
-		resultValue = reflect.New(resultType).Elem()
-	}
-
-	var fields []*Field
-
-	for i := 0; i < resultType.NumField(); i++ {
-		fields = append(fields, NewField(resultValue.Field(i), resultType.Field(i)))
-	}
-
 	for rows.Next() {
-		err := c.scan(rows, columns, fields)
+		resultValue := reflect.New(resultType).Elem()
+
+		err := c.scanRow(rows, columns, resultValue)
 		if err != nil {
 			return err
 		}
-		if kind == reflect.Slice {
-			if isPtr {
-				resultValue = resultValue.Addr()
-			}
-			results.Set(reflect.Append(results, resultValue))
-		} else {
-			break
+
+		if isPtr {
+			resultValue = resultValue.Addr()
 		}
+		results.Set(reflect.Append(results, resultValue))
 	}
 
-	return nil
+	return rows.Err()
 }
 
 func (c *Connection) Scan(query string, bindings []interface{}, dest ...interface{}) error {
-	log.Println(query)
+	_, err := c.run(query, bindings, func() (int64, error) {
+		stmt, err := c.readExecutor().PrepareContext(c.Context(), query)
 
-	var err error
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
 
-	stmt, err := c.DB.Prepare(query)
+		rows, err := stmt.QueryContext(c.Context(), bindings...)
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
 
+		rows.Next()
+
+		return 0, rows.Scan(dest...)
+	})
+	return err
+}
+
+// Insert Run an insert statement against the database and get the id of the
+// inserted row, 0 unless the grammar supports LastInsertId, and the number of
+// affected rows.
+func (c *Connection) Insert(query string, args ...interface{}) (int64, int64, error) {
+	result, err := c.affectingStatement(query, args...)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query(bindings...)
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return 0, affected, err
 	}
-	defer rows.Close()
 
-	rows.Next()
+	// Without driver support the id of the inserted row is read back by
+	// InsertGetId instead.
+	if !c.GetQueryGrammar().SupportsLastInsertId() {
+		return 0, affected, nil
+	}
 
-	return rows.Scan(dest...)
+	insertId, err := result.LastInsertId()
+	return insertId, affected, err
 }
 
-// Insert Run an insert statement against the database.
-func (c *Connection) Insert(query string, args ...interface{}) (int64, int64, error) {
-	return c.affectingStatement(query, args...)
+// InsertGetId Run an insert statement compiled by Grammar.CompileInsertGetId
+// and get the id of the inserted row.
+func (c *Connection) InsertGetId(query string, args ...interface{}) (int64, error) {
+	if !c.GetQueryGrammar().SupportsReturning() {
+		insertId, _, err := c.Insert(query, args...)
+		return insertId, err
+	}
+
+	var insertId int64
+	_, err := c.run(query, args, func() (int64, error) {
+		err := c.executor().QueryRowContext(c.Context(), query, args...).Scan(&insertId)
+		if err != nil {
+			return 0, err
+		}
+		c.recordModified()
+		return 1, nil
+	})
+
+	return insertId, err
 }
 
 // Update Run an update statement against the database.
 func (c *Connection) Update(query string, args ...interface{}) (int64, error) {
-	_, affected, err := c.affectingStatement(query, args...)
-	return affected, err
+	result, err := c.affectingStatement(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 // Delete Run a delete statement against the database.
 func (c *Connection) Delete(query string, args ...interface{}) (int64, error) {
-	_, affected, err := c.affectingStatement(query, args...)
-	return affected, err
+	result, err := c.affectingStatement(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 // Statement Execute an SQL statement and return the boolean result.
 func (c *Connection) Statement(query string, args ...interface{}) error {
-	var err error
+	_, err := c.affectingStatement(query, args...)
+	return err
+}
 
-	stmt, err := c.DB.Prepare(query)
-	if err != nil {
-		return err
+// GetQueryGrammar Get the query grammar used by the connection.
+func (c *Connection) GetQueryGrammar() grammar.Grammar {
+	if c.grammar == nil {
+		c.grammar = grammar.NewMySqlGrammar()
+		c.grammar.SetTablePrefix(c.tablePrefix)
 	}
-	defer stmt.Close()
+	return c.grammar
+}
 
-	_, err = stmt.Exec(args...)
+// GetTablePrefix Get the prefix of the table names of the connection.
+func (c *Connection) GetTablePrefix() string {
+	return c.tablePrefix
+}
 
-	return err
+// SetTablePrefix Set the prefix of the table names of the connection.
+func (c *Connection) SetTablePrefix(prefix string) {
+	c.tablePrefix = prefix
+	c.GetQueryGrammar().SetTablePrefix(prefix)
 }
 
-func (c *Connection) GetQueryGrammar() grammar.Grammar {
-	return &grammar.MySqlGrammar{}
+// executor Get the transaction the connection is bound to, or the writer.
+func (c *Connection) executor() executor {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.DB
 }
 
-// AffectingStatement Run an SQL statement and get the number of rows affected.
-func (c *Connection) affectingStatement(query string, args ...interface{}) (int64, int64, error) {
-	var err error
+// readExecutor Get the executor select statements run on: a random reader,
+// unless the connection is bound to a transaction, was told to use the
+// writer, or has written with the Sticky option.
+func (c *Connection) readExecutor() executor {
+	if c.tx != nil || c.forceWriter || len(c.readers) == 0 {
+		return c.executor()
+	}
+	if c.sticky && c.modified != nil && atomic.LoadInt32(c.modified) == 1 {
+		return c.DB
+	}
+	return c.readers[rand.Intn(len(c.readers))]
+}
 
-	log.Println(query)
+// recordModified Remember that the connection has written.
+func (c *Connection) recordModified() {
+	if c.modified != nil {
+		atomic.StoreInt32(c.modified, 1)
+	}
+}
 
-	stmt, err := c.DB.Prepare(query)
+// AffectingStatement Run an SQL statement and get the result of its execution.
+func (c *Connection) affectingStatement(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
 
-	if err != nil {
-		return 0, 0, err
-	}
-	defer stmt.Close()
+	_, err := c.run(query, args, func() (int64, error) {
+		stmt, err := c.executor().PrepareContext(c.Context(), query)
 
-	result, err := stmt.Exec(args...)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
 
-	if err != nil {
-		return 0, 0, err
+		if result, err = stmt.ExecContext(c.Context(), args...); err != nil {
+			return 0, err
+		}
+		c.recordModified()
+
+		// Not every driver reports the affected rows of every statement.
+		affected, _ := result.RowsAffected()
+		return affected, nil
+	})
+
+	return result, err
+}
+
+// scanRow Scan the current row into a struct, a map[string]interface{} keyed
+// by the column names, or, for any other type, the value of its first column.
+func (c *Connection) scanRow(rows *sql.Rows, columns []string, value reflect.Value) error {
+	switch {
+	case value.Kind() == reflect.Map:
+		return scanMap(rows, columns, value)
+	case value.Kind() == reflect.Struct && !isScalar(value.Type()):
+		return c.scan(rows, columns, structFields(value))
 	}
 
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return 0, affected, err
+	args := make([]interface{}, len(columns))
+	for i := range args {
+		args[i] = new(interface{})
+	}
+	if len(args) > 0 {
+		args[0] = value.Addr().Interface()
 	}
 
-	insertId, err := result.LastInsertId()
-	return insertId, affected, err
+	return rows.Scan(args...)
 }
 
 func (c *Connection) scan(rows *sql.Rows, columns []string, fields []*Field) error {
@@ -233,3 +391,42 @@ func (c *Connection) scan(rows *sql.Rows, columns []string, fields []*Field) err
 
 	return nil
 }
+
+// scanMap Scan the current row into a map[string]interface{} keyed by the
+// column names. Text read as bytes is stored as a string.
+func scanMap(rows *sql.Rows, columns []string, value reflect.Value) error {
+	if value.Type().Key().Kind() != reflect.String || value.Type().Elem().Kind() != reflect.Interface {
+		return errors.New("unsupported destination, maps should be map[string]interface{}")
+	}
+
+	values := make([]interface{}, len(columns))
+	args := make([]interface{}, len(columns))
+	for i := range values {
+		args[i] = &values[i]
+	}
+
+	if err := rows.Scan(args...); err != nil {
+		return err
+	}
+
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(value.Type(), len(columns)))
+	}
+
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+
+		item := reflect.ValueOf(&values[i]).Elem()
+		value.SetMapIndex(reflect.ValueOf(column).Convert(value.Type().Key()), item)
+	}
+
+	return nil
+}
+
+// isScalar Determine if values of a struct type are read from a single
+// column, like time.Time and sql.NullString, rather than field by field.
+func isScalar(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || reflect.PtrTo(t).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem())
+}
@@ -0,0 +1,91 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuilderWithCancelledContext(t *testing.T) {
+	conn := newTestConnection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var users []testUser
+	err := conn.Table("users").WithContext(ctx).Get(&users)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("invalid select error: got:%v want:%v", err, context.Canceled)
+	}
+
+	_, _, err = conn.Table("users").WithContext(ctx).Insert(map[string]interface{}{"name": "uno"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("invalid insert error: got:%v want:%v", err, context.Canceled)
+	}
+
+	if conn.Context() != context.Background() {
+		t.Error("expecting the original connection to keep the background context")
+	}
+}
+
+func TestReadWriteSplitting(t *testing.T) {
+	dir := t.TempDir()
+	writer, reader := filepath.Join(dir, "writer.db"), filepath.Join(dir, "reader.db")
+
+	for _, dsn := range []string{writer, reader} {
+		conn, err := Open(Config{Driver: "sqlite3", Dsn: dsn})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.Statement("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255))"); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+	}
+
+	conn, err := Open(Config{Driver: "sqlite3", Dsn: writer, Read: []string{reader}, Sticky: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	request := conn.WithContext(context.Background())
+	insertUsers(t, request, "ana")
+
+	// The replica never receives the write, so only the writer has the user.
+	if count := countUsers(t, conn); count != 0 {
+		t.Errorf("invalid reader count: got:%d want:%d", count, 0)
+	}
+	if count := countUsers(t, request); count != 1 {
+		t.Errorf("invalid sticky count: got:%d want:%d", count, 1)
+	}
+
+	var users []testUser
+	if err := conn.Table("users").LockForUpdate().Get(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Errorf("invalid locked count: got:%d want:%d", len(users), 1)
+	}
+
+	err = conn.Transaction(func(tx *Connection) error {
+		if count := countUsers(t, tx); count != 1 {
+			t.Errorf("invalid transaction count: got:%d want:%d", count, 1)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes through the root connection belong to no request, so its reads
+	// keep going to the replica.
+	insertUsers(t, conn, "bob")
+	if count := countUsers(t, conn); count != 0 {
+		t.Errorf("invalid root reader count: got:%d want:%d", count, 0)
+	}
+	if count := countUsers(t, conn.WithContext(context.Background())); count != 0 {
+		t.Errorf("invalid new request count: got:%d want:%d", count, 0)
+	}
+}
@@ -0,0 +1,95 @@
+package orm
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// Cursor Iterates over the results of a select statement one row at a time,
+// so that large result sets never have to fit in memory:
+//
+//	cursor, err := conn.Table("users").Cursor()
+//	defer cursor.Close()
+//	for cursor.Next() {
+//		var user User
+//		err := cursor.Scan(&user)
+//	}
+//	err = cursor.Err()
+type Cursor struct {
+	conn    *Connection
+	stmt    *sql.Stmt
+	rows    *sql.Rows
+	columns []string
+}
+
+// Cursor Get a cursor over the results of the query. The cursor should be
+// closed once done with it.
+func (b *Builder) Cursor() (*Cursor, error) {
+	return b.Connection.Cursor(b.ToSql(), b.GetBindings())
+}
+
+// Cursor Run a select statement against the database and get a cursor over
+// its results.
+func (c *Connection) Cursor(query string, bindings []interface{}) (*Cursor, error) {
+	var cursor *Cursor
+
+	_, err := c.run(query, bindings, func() (int64, error) {
+		stmt, err := c.readExecutor().PrepareContext(c.Context(), query)
+		if err != nil {
+			return 0, err
+		}
+
+		rows, err := stmt.QueryContext(c.Context(), bindings...)
+		if err != nil {
+			stmt.Close()
+			return 0, err
+		}
+
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			stmt.Close()
+			return 0, err
+		}
+
+		cursor = &Cursor{conn: c, stmt: stmt, rows: rows, columns: columns}
+		return 0, nil
+	})
+
+	return cursor, err
+}
+
+// Next Advance the cursor to the next row, reporting whether there is one.
+func (c *Cursor) Next() bool {
+	return c.rows.Next()
+}
+
+// Scan Scan the current row into the struct, map[string]interface{} or
+// single value dest points to.
+func (c *Cursor) Scan(dest interface{}) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("unsupported destination, should be pointer")
+	}
+
+	if err := c.conn.scanRow(c.rows, c.columns, value.Elem()); err != nil {
+		return err
+	}
+
+	return callHooks(dest, c.conn, afterFind)
+}
+
+// Err Get the error, if any, that was encountered during iteration.
+func (c *Cursor) Err() error {
+	return c.rows.Err()
+}
+
+// Close Close the cursor, releasing its statement.
+func (c *Cursor) Close() error {
+	err := c.rows.Close()
+	if serr := c.stmt.Close(); err == nil {
+		err = serr
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package orm
+
+import "testing"
+
+func TestChunk(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan", "eve")
+
+	var sizes []int
+	var users []testUser
+	err := conn.Table("users").OrderBy("id").Chunk(2, &users, func(batch interface{}) error {
+		sizes = append(sizes, len(*batch.(*[]testUser)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{2, 2, 1}
+	if len(sizes) != len(want) {
+		t.Fatalf("invalid chunk count: got:%v want:%v", sizes, want)
+	}
+	for i := range sizes {
+		if sizes[i] != want[i] {
+			t.Errorf("invalid chunk %d size: got:%d want:%d", i, sizes[i], want[i])
+		}
+	}
+}
+
+func TestChunkById(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan", "eve")
+
+	// Deleting the rows of every batch would make Chunk skip pages.
+	var names []string
+	var users []testUser
+	err := conn.Table("users").Where("name", "<>", "cid").ChunkById(2, &users, func(batch interface{}) error {
+		for _, user := range *batch.(*[]testUser) {
+			names = append(names, user.Name)
+			if _, err := conn.Table("users").Where("id", user.ID).Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"ana", "bob", "dan", "eve"}
+	if len(names) != len(want) {
+		t.Fatalf("invalid chunked names: got:%v want:%v", names, want)
+	}
+	for i := range names {
+		if names[i] != want[i] {
+			t.Errorf("invalid chunked name %d: got:%s want:%s", i, names[i], want[i])
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid")
+
+	cursor, err := conn.Table("users").Where("name", ">", "ana").OrderBy("id").Cursor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+
+	var names []string
+	for cursor.Next() {
+		var user testUser
+		if err := cursor.Scan(&user); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, user.Name)
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 2 || names[0] != "bob" || names[1] != "cid" {
+		t.Errorf("invalid cursor names: got:%v want:%v", names, []string{"bob", "cid"})
+	}
+}
@@ -2,16 +2,76 @@ package orm
 
 import (
 	"database/sql"
+	"fmt"
+
+	"github.com/glugox/unogo/orm/grammar"
 )
 
 func Open(config Config) (*Connection, error) {
+	queryGrammar, err := newQueryGrammar(config.Driver)
+
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := sql.Open(config.Driver, config.Dsn)
 
 	if err != nil {
 		return nil, err
 	}
 
+	var readers []*sql.DB
+	for _, dsn := range config.Read {
+		reader, err := sql.Open(config.Driver, dsn)
+		if err != nil {
+			db.Close()
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+
+	for _, d := range append([]*sql.DB{db}, readers...) {
+		configurePool(d, config)
+	}
+
+	queryGrammar.SetTablePrefix(config.Prefix)
+
 	return &Connection{
-		DB: db,
+		DB:          db,
+		tablePrefix: config.Prefix,
+		readers:     readers,
+		sticky:      config.Sticky,
+		grammar:     queryGrammar,
+		listeners:   &listeners{},
 	}, nil
 }
+
+// configurePool Apply the connection pool settings of the config.
+func configurePool(db *sql.DB, config Config) {
+	if config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+}
+
+// newQueryGrammar Get the query grammar matching a database/sql driver name.
+func newQueryGrammar(driver string) (grammar.Grammar, error) {
+	switch driver {
+	case "mysql":
+		return grammar.NewMySqlGrammar(), nil
+	case "sqlite3", "sqlite":
+		return grammar.NewSqliteGrammar(), nil
+	case "postgres", "pgx":
+		return grammar.NewPostgresGrammar(), nil
+	}
+
+	return nil, fmt.Errorf("database driver [%s] not supported", driver)
+}
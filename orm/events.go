@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/glugox/unogo/log"
+)
+
+// QueryEvent A statement run by a connection.
+type QueryEvent struct {
+	Sql      string
+	Bindings []interface{}
+
+	// Time How long running the statement took.
+	Time time.Duration
+
+	// RowsAffected The number of rows changed by an insert, update or delete
+	// statement, 0 for other statements.
+	RowsAffected int64
+
+	// Err The error the statement failed with, if any.
+	Err error
+}
+
+// listeners The query listeners of a connection, shared by the copies made of
+// it for transactions and contexts.
+type listeners struct {
+	sync.RWMutex
+	callbacks []func(QueryEvent)
+}
+
+// Listen Register a listener called after every statement the connection
+// runs, e.g. for logging or collecting metrics.
+func (c *Connection) Listen(callback func(QueryEvent)) {
+	if c.listeners == nil {
+		c.listeners = &listeners{}
+	}
+
+	c.listeners.Lock()
+	defer c.listeners.Unlock()
+
+	c.listeners.callbacks = append(c.listeners.callbacks, callback)
+}
+
+// run Run a statement through the callback, timing it and passing its
+// QueryEvent to the listeners.
+func (c *Connection) run(query string, bindings []interface{}, callback func() (int64, error)) (int64, error) {
+	start := time.Now()
+	affected, err := callback()
+
+	if c.listeners != nil {
+		event := QueryEvent{
+			Sql:          query,
+			Bindings:     bindings,
+			Time:         time.Since(start),
+			RowsAffected: affected,
+			Err:          err,
+		}
+
+		c.listeners.RLock()
+		defer c.listeners.RUnlock()
+
+		for _, listener := range c.listeners.callbacks {
+			listener(event)
+		}
+	}
+
+	return affected, err
+}
+
+// LogQueries Get a query listener writing every statement to the logger at
+// the DEBUG level. Statements slower than the threshold are written at the
+// WARNING level, failed ones at the ERROR level. A zero threshold never
+// flags a statement as slow.
+func LogQueries(logger *log.Logger, threshold time.Duration) func(QueryEvent) {
+	return func(event QueryEvent) {
+		switch {
+		case event.Err != nil:
+			logger.Error("%s %v [%s]: %s", event.Sql, event.Bindings, event.Time, event.Err)
+		case threshold > 0 && event.Time > threshold:
+			logger.Warn("slow query %s %v [%s]", event.Sql, event.Bindings, event.Time)
+		default:
+			logger.Debug("%s %v [%s]", event.Sql, event.Bindings, event.Time)
+		}
+	}
+}
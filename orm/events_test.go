@@ -0,0 +1,48 @@
+package orm
+
+import (
+	"testing"
+)
+
+func TestListen(t *testing.T) {
+	conn := newTestConnection(t)
+
+	var events []QueryEvent
+	conn.Listen(func(event QueryEvent) {
+		events = append(events, event)
+	})
+
+	err := conn.Transaction(func(tx *Connection) error {
+		_, err := tx.Table("users").Where("name", "ana").Update(map[string]interface{}{"name": "bob"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insertUsers(t, conn, "ana", "ana")
+	if _, err := conn.Table("users").Where("name", "ana").Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Statement("SELECT * FROM missing"); err == nil {
+		t.Fatal("expected an error for a missing table")
+	}
+
+	if len(events) != 5 {
+		t.Fatalf("invalid event count: got:%d want:%d", len(events), 5)
+	}
+
+	update := events[0]
+	if len(update.Bindings) != 2 || update.Bindings[1] != "ana" {
+		t.Errorf("invalid update bindings: got:%v", update.Bindings)
+	}
+
+	if deleted := events[3]; deleted.RowsAffected != 2 {
+		t.Errorf("invalid delete rows affected: got:%d want:%d", deleted.RowsAffected, 2)
+	}
+
+	if failed := events[4]; failed.Err == nil || failed.Sql != "SELECT * FROM missing" {
+		t.Errorf("invalid failed event: got:%+v", failed)
+	}
+}
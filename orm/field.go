@@ -19,6 +19,7 @@ type Field struct {
 	Name        string
 	Ignored     bool
 	IsBlank     bool
+	Relation    string
 }
 
 // GetParams Get the attr of tag
@@ -95,6 +96,9 @@ func (f *Field) initialize() {
 			f.Primary = true
 		case "COLUMN":
 			f.Name = f.Attrs[k]
+		case HasOne, HasMany, BelongsTo, BelongsToMany:
+			f.Relation = k
+			f.Ignored = true
 		}
 	}
 
@@ -15,5 +15,5 @@ type UpdatedAtAttr struct {
 }
 
 type DeletedAtAttr struct {
-	DeletedAt time.Time `torm:"deleted_at" json:"deleted_at"`
+	DeletedAt *time.Time `torm:"deleted_at" json:"deleted_at"`
 }
@@ -1,8 +1,45 @@
 package grammar
 
-import "sort"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+var selectComponents = []string{
+	"aggregate",
+	"columns",
+	"from",
+	"joins",
+	"wheres",
+	"groups",
+	"havings",
+	"orders",
+	"limit",
+	"offset",
+	"lock",
+}
+
+// dialect The pieces of SQL syntax that differ between databases. Each grammar
+// implements it and registers itself on the embedded BaseGrammar, so that the
+// shared compilers below can call back into the concrete grammar.
+type dialect interface {
+	// wrapValue Wrap a single string in keyword identifiers.
+	wrapValue(value string) string
+
+	// wrapUnion Wrap a select statement taking part in a union.
+	wrapUnion(sql string) string
+
+	// compileLock Compile the lock into SQL.
+	compileLock(query *query.Query, lock *query.Lock) string
+}
 
 type BaseGrammar struct {
+	dialect     dialect
+	tablePrefix string
 }
 
 func (g *BaseGrammar) Parameterize(values []interface{}) string {
@@ -30,7 +67,9 @@ func (g *BaseGrammar) IsExpression(value interface{}) bool {
 	return ok
 }
 
-// PrepareBindingsForUpdate Prepare the bindings for an update statement.
+// PrepareBindingsForUpdate Prepare the bindings for an update statement, in
+// the order of their place-holders: joins, the updated values sorted by
+// column, wheres and orders.
 func (g *BaseGrammar) PrepareBindingsForUpdate(bindings map[string][]interface{}, values map[string]interface{}) []interface{} {
 
 	var results []interface{}
@@ -49,16 +88,650 @@ func (g *BaseGrammar) PrepareBindingsForUpdate(bindings map[string][]interface{}
 		results = append(results, values[key])
 	}
 
-	for kb, vb := range bindings {
-		if kb == "join" {
-			continue
+	// An update statement only compiles the joins, wheres and orders of the
+	// query, so the other bindings have no place-holders.
+	for _, segment := range []string{"where", "order"} {
+		results = append(results, bindings[segment]...)
+	}
+	return results
+}
+
+// CompileSelect Compile a select query into SQL.
+func (g *BaseGrammar) CompileSelect(query *query.Query) string {
+	if len(query.Unions) > 0 && query.Aggregate != nil {
+		return g.compileUnionAggregate(query)
+	}
+
+	// If the query does not have any columns set, we'll set the columns to the
+	// * character to just get all of the columns from the database. Then we
+	// can build the query and concatenate all the pieces together as one.
+	original := query.Columns
+
+	if query.Columns == nil {
+		query.Columns = []interface{}{"*"}
+	}
+
+	// To compile the query, we'll spin through each component of the query and
+	// see if that component exists. If it does we'll just call the compiler
+	// function for the component which is responsible for making the SQL.
+	sql := strings.TrimSpace(
+		g.concatenate(
+			g.compileComponents(query),
+		),
+	)
+
+	if len(query.Unions) > 0 {
+		sql = g.dialect.wrapUnion(sql) + " " + g.compileUnions(query)
+	}
+
+	query.Columns = original
+	return sql
+}
+
+// GetTablePrefix Get the prefix of the table names.
+func (g *BaseGrammar) GetTablePrefix() string {
+	return g.tablePrefix
+}
+
+// SetTablePrefix Set the prefix of the table names.
+func (g *BaseGrammar) SetTablePrefix(prefix string) {
+	g.tablePrefix = prefix
+}
+
+// WrapTable Wrap a table in keyword identifiers, adding the table prefix to
+// the table and to its alias, e.g. "users as u". The schema of a qualified
+// table, "db.users", is left without the prefix.
+func (g *BaseGrammar) WrapTable(table string) string {
+	table, alias, aliased := splitAlias(table)
+
+	segments := strings.Split(table, ".")
+	segments[len(segments)-1] = g.tablePrefix + segments[len(segments)-1]
+	for key, segment := range segments {
+		segments[key] = g.dialect.wrapValue(segment)
+	}
+
+	sql := strings.Join(segments, ".")
+	if aliased {
+		sql += " as " + g.dialect.wrapValue(g.tablePrefix+alias)
+	}
+	return sql
+}
+
+// Wrap Wrap a value in keyword identifiers. The value may be qualified by
+// its table, "users.name", and aliased, "name as username"; the table prefix
+// is added to the table and, with prefixAlias, to the alias as well.
+func (g *BaseGrammar) Wrap(value string, prefixAlias bool) string {
+	if column, alias, ok := splitAlias(value); ok {
+		if prefixAlias {
+			alias = g.tablePrefix + alias
 		}
-		if kb == "select" {
+		return g.Wrap(column, false) + " as " + g.dialect.wrapValue(alias)
+	}
+
+	return g.wrapSegments(strings.Split(value, "."))
+}
+
+// wrapColumn Wrap a column, leaving a raw expression created by query.Expr
+// as it is.
+func (g *BaseGrammar) wrapColumn(column interface{}) string {
+	if g.IsExpression(column) {
+		return fmt.Sprint(column.(Expression).GetValue())
+	}
+	return g.Wrap(fmt.Sprint(column), false)
+}
+
+// wrapSegments Wrap the segments of a column, adding the table prefix to the
+// table that qualifies it, the segment before the column name.
+func (g *BaseGrammar) wrapSegments(segments []string) string {
+	for key, segment := range segments {
+		if key == len(segments)-2 {
+			segments[key] = g.dialect.wrapValue(g.tablePrefix + segment)
+		} else {
+			segments[key] = g.dialect.wrapValue(segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// splitAlias Split an aliased value, "value as alias", into its parts.
+func splitAlias(value string) (string, string, bool) {
+	if i := strings.Index(strings.ToLower(value), " as "); i >= 0 {
+		return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+4:]), true
+	}
+	return value, "", false
+}
+
+func (g *BaseGrammar) compileComponents(query *query.Query) []string {
+	var sql []string
+	for _, component := range selectComponents {
+		switch component {
+		case "aggregate":
+			if query.Aggregate != nil {
+				sql = append(sql, g.compileAggregate(query, query.Aggregate))
+			}
+		case "columns":
+			if len(query.Columns) > 0 {
+				sql = append(sql, g.compileColumns(query, query.Columns))
+			}
+		case "from":
+			if len(query.From) > 0 {
+				sql = append(sql, g.compileFrom(query, query.From))
+			}
+		case "joins":
+			if len(query.Joins) > 0 {
+				sql = append(sql, g.compileJoins(query, query.Joins))
+			}
+		case "wheres":
+			if len(query.Wheres) > 0 {
+				sql = append(sql, g.compileWheres(query))
+			}
+		case "groups":
+			if len(query.Groups) > 0 {
+				sql = append(sql, g.compileGroups(query, query.Groups))
+			}
+		case "havings":
+			if len(query.Havings) > 0 {
+				sql = append(sql, g.compileHavings(query, query.Havings))
+			}
+		case "orders":
+			if len(query.Orders) > 0 {
+				sql = append(sql, g.compileOrders(query, query.Orders))
+			}
+
+		case "limit":
+			if query.Limit > 0 {
+				sql = append(sql, g.compileLimit(query, query.Limit))
+			}
+		case "offset":
+			if query.Limit > 0 {
+				sql = append(sql, g.compileOffset(query, query.Offset))
+			}
+		case "lock":
+			if query.Lock != nil {
+				sql = append(sql, g.dialect.compileLock(query, query.Lock))
+			}
+		}
+	}
+	return sql
+}
+
+// compileUnions Compile the union queries attached to the main query, along
+// with the order, limit and offset that apply to the union result.
+func (g *BaseGrammar) compileUnions(q *query.Query) string {
+	var sql []string
+
+	for _, union := range q.Unions {
+		conjunction := "UNION"
+		if union.All {
+			conjunction = "UNION ALL"
+		}
+		sql = append(sql, conjunction+" "+g.dialect.wrapUnion(g.CompileSelect(union.Query)))
+	}
+
+	if len(q.UnionOrders) > 0 {
+		var orders []*query.Order
+		for _, order := range q.UnionOrders {
+			orders = append(orders, &query.Order{
+				Type:      order.Type,
+				Sql:       order.Sql,
+				Column:    order.Column,
+				Direction: order.Direction,
+			})
+		}
+		sql = append(sql, g.compileOrders(q, orders))
+	}
+
+	if q.UnionLimit > 0 {
+		sql = append(sql, g.compileLimit(q, q.UnionLimit))
+	}
+
+	if q.UnionOffset > 0 {
+		sql = append(sql, g.compileOffset(q, q.UnionOffset))
+	}
+
+	return strings.Join(sql, " ")
+}
+
+// compileUnionAggregate Compile an aggregate over the result of a union query.
+func (g *BaseGrammar) compileUnionAggregate(q *query.Query) string {
+	inner := *q
+	inner.Aggregate = nil
+
+	sql := g.compileAggregate(q, q.Aggregate)
+
+	return sql + " FROM (" + g.CompileSelect(&inner) + ") AS " + g.dialect.wrapValue("temp_table")
+}
+
+// wrapUnion Wrap a select statement taking part in a union.
+func (g *BaseGrammar) wrapUnion(sql string) string {
+	return "(" + sql + ")"
+}
+
+func (g *BaseGrammar) concatenate(segments []string) string {
+	s := ""
+	for _, segment := range segments {
+		if len(segment) == 0 {
 			continue
 		}
-		for _, val := range vb {
-			results = append(results, val)
+		if len(s) > 0 {
+			s = s + " "
 		}
+		s = s + segment
 	}
-	return results
+	return s
+}
+
+func (g *BaseGrammar) compileAggregate(query *query.Query, aggregate *query.Aggregate) string {
+	var columns []string
+	for _, column := range aggregate.Columns {
+		columns = append(columns, g.Wrap(column, false))
+	}
+
+	column := strings.Join(columns, ", ")
+	if query.Distinct && column != "*" {
+		column = "DISTINCT " + column
+	}
+
+	return "SELECT " + aggregate.Function + "(" + column + ") AS aggregate"
+}
+
+func (g *BaseGrammar) compileColumns(q *query.Query, columns []interface{}) string {
+	// If the query is actually performing an aggregating select, we will let that
+	// compiler handle the building of the select clauses, as it will need some
+	// more syntax that is best handled by that function to keep things neat.
+	if q.Aggregate != nil {
+		return ""
+	}
+
+	sel := "SELECT "
+	if q.Distinct {
+		sel = "SELECT DISTINCT "
+	}
+
+	var sql []string
+	for _, column := range columns {
+		switch c := column.(type) {
+		case *query.Sub:
+			sql = append(sql, g.compileSub(c))
+		default:
+			sql = append(sql, g.wrapColumn(c))
+		}
+	}
+
+	return sel + strings.Join(sql, ", ")
+}
+
+// compileSub Compile an aliased subquery.
+func (g *BaseGrammar) compileSub(sub *query.Sub) string {
+	return "(" + g.CompileSelect(sub.Query) + ") AS " + g.dialect.wrapValue(sub.As)
+}
+
+func (g *BaseGrammar) compileFrom(query *query.Query, table string) string {
+	if query.FromSub != nil {
+		// The alias gets the table prefix, as an aliased table does, so that
+		// the columns qualified by it match.
+		return "FROM (" + g.CompileSelect(query.FromSub.Query) + ") AS " + g.dialect.wrapValue(g.tablePrefix+query.FromSub.As)
+	}
+	return "FROM " + g.WrapTable(table)
+}
+
+func (g *BaseGrammar) compileJoins(query *query.Query, joins []*query.Join) string {
+	var segments []string
+	for _, join := range joins {
+		table := g.WrapTable(join.Table)
+		segments = append(segments, strings.TrimSpace(join.Type+" JOIN "+table+" "+g.compileWheres(join.Query)))
+	}
+	return strings.Join(segments, " ")
+}
+
+func (g *BaseGrammar) compileWheres(query *query.Query) string {
+
+	sql := g.compileWheresToArray(query)
+	if len(sql) > 0 {
+		return g.concatenateWhereClauses(query, sql)
+	}
+
+	return ""
+}
+
+func (g *BaseGrammar) compileWheresToArray(query *query.Query) []string {
+	var sql []string
+	for _, where := range query.Wheres {
+		w := ""
+		switch where.Type {
+		case "Basic":
+			w = where.Boolean + " " + g.whereBasic(query, where)
+		case "In":
+			w = where.Boolean + " " + g.whereIn(query, where)
+		case "NotIn":
+			w = where.Boolean + " " + g.whereNotIn(query, where)
+		case "Null":
+			w = where.Boolean + " " + g.whereNull(query, where)
+		case "NotNull":
+			w = where.Boolean + " " + g.whereNotNull(query, where)
+		case "Between":
+			w = where.Boolean + " " + g.whereBetween(query, where)
+		case "Column":
+			w = where.Boolean + " " + g.whereColumn(query, where)
+		case "Nested":
+			w = where.Boolean + " " + g.whereNested(query, where)
+		case "Exists":
+			w = where.Boolean + " " + g.whereExists(query, where)
+		case "InSub":
+			w = where.Boolean + " " + g.whereInSub(query, where)
+		case "Raw":
+			w = where.Boolean + " " + where.Sql
+
+		}
+		sql = append(sql, w)
+	}
+	return sql
+}
+
+func (g *BaseGrammar) concatenateWhereClauses(query *query.Query, sql []string) string {
+	conjunction := "WHERE"
+	if query.JoinClause {
+		conjunction = "ON"
+	}
+	return conjunction + " " + removeLeadingBoolean(strings.Join(sql, " "))
+}
+
+func (g *BaseGrammar) whereBasic(query *query.Query, where *query.Where) string {
+	// value = where.Value
+	return g.Wrap(where.Column, false) + " " + where.Operator + " " + fmt.Sprint(g.Parameter(where.Value))
+}
+
+func (g *BaseGrammar) whereIn(query *query.Query, where *query.Where) string {
+	if len(where.Values) > 0 {
+		return g.Wrap(where.Column, false) + " IN (" + g.Parameterize(where.Values) + ")"
+	}
+	return "0 = 1"
+}
+
+func (g *BaseGrammar) whereNotIn(query *query.Query, where *query.Where) string {
+	if len(where.Values) > 0 {
+		return g.Wrap(where.Column, false) + " NOT IN (" + g.Parameterize(where.Values) + ")"
+	}
+	return "1 = 1"
+}
+
+func (g *BaseGrammar) whereNull(query *query.Query, where *query.Where) string {
+	return g.Wrap(where.Column, false) + " IS NULL"
+}
+
+func (g *BaseGrammar) whereNotNull(query *query.Query, where *query.Where) string {
+	return g.Wrap(where.Column, false) + " IS NOT NULL"
+}
+
+func (g *BaseGrammar) whereBetween(query *query.Query, where *query.Where) string {
+	between := "between"
+	if where.Not {
+		between = "not between"
+	}
+
+	return g.Wrap(where.Column, false) + " " + between + " ? and ?"
+}
+
+func (g *BaseGrammar) whereColumn(query *query.Query, where *query.Where) string {
+	return g.Wrap(where.First, false) + " " + where.Operator + " " + g.Wrap(where.Second, false)
+}
+
+func (g *BaseGrammar) whereNested(query *query.Query, where *query.Where) string {
+	return "(" + removeLeadingBoolean(strings.Join(g.compileWheresToArray(where.Query), " ")) + ")"
+}
+
+func (g *BaseGrammar) whereExists(query *query.Query, where *query.Where) string {
+	exists := "EXISTS"
+	if where.Not {
+		exists = "NOT EXISTS"
+	}
+
+	return exists + " (" + g.CompileSelect(where.Query) + ")"
+}
+
+func (g *BaseGrammar) whereInSub(query *query.Query, where *query.Where) string {
+	in := " IN "
+	if where.Not {
+		in = " NOT IN "
+	}
+
+	return g.Wrap(where.Column, false) + in + "(" + g.CompileSelect(where.Query) + ")"
+}
+
+func (g *BaseGrammar) compileGroups(query *query.Query, groups []interface{}) string {
+	var sql []string
+	for _, group := range groups {
+		sql = append(sql, g.wrapColumn(group))
+	}
+	return "GROUP BY " + strings.Join(sql, ", ")
+}
+
+func (g *BaseGrammar) compileHavings(query *query.Query, havings []*query.Having) string {
+	sqls := make([]string, 0)
+	for _, having := range havings {
+		sqls = append(sqls, g.compileHaving(having))
+	}
+	sql := strings.Join(sqls, " ")
+	return "HAVING " + removeLeadingBoolean(sql)
+}
+
+func (g *BaseGrammar) compileHaving(having *query.Having) string {
+	if having.Type == "Raw" {
+		return having.Boolean + " " + having.Sql
+	}
+	return g.compileBasicHaving(having)
+}
+
+func (g *BaseGrammar) compileBasicHaving(having *query.Having) string {
+	return having.Boolean + " " + g.Wrap(having.Column, false) + " " + having.Operator + " " + "?"
+}
+
+func (g *BaseGrammar) compileOrders(query *query.Query, orders []*query.Order) string {
+	var sql []string
+
+	for _, order := range orders {
+		s := ""
+		if len(order.Sql) > 0 {
+			s = order.Sql
+		} else {
+			s = g.Wrap(order.Column, false) + " " + order.Direction
+		}
+		sql = append(sql, s)
+	}
+
+	if len(sql) == 0 {
+		return ""
+	}
+
+	return "ORDER BY " + strings.Join(sql, ", ")
+}
+
+func (g *BaseGrammar) compileLimit(query *query.Query, limit uint64) string {
+	return fmt.Sprintf("LIMIT %v", limit)
+}
+
+func (g *BaseGrammar) compileOffset(query *query.Query, offset uint64) string {
+	return fmt.Sprintf("OFFSET %v", offset)
+}
+
+// compileLock Compile the lock into SQL.
+func (g *BaseGrammar) compileLock(query *query.Query, lock *query.Lock) string {
+	sql := "FOR UPDATE"
+	if lock.Shared {
+		sql = "FOR SHARE"
+	}
+
+	return strings.TrimSpace(sql + " " + lock.Option)
+}
+
+// CompileInsert Compile an insert statement into SQL.
+func (g *BaseGrammar) CompileInsert(query *query.Query, values []map[string]interface{}) (string, []interface{}) {
+	table := g.WrapTable(query.From)
+	var columns []string
+	var parameters []string
+	var bindings []interface{}
+
+	if len(values) > 0 {
+		first := values[0]
+		for k := range first {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+		for _, val := range values {
+			var vals []string
+			for _, column := range columns {
+				if col, ok := val[column]; ok {
+					bindings = append(bindings, col)
+					vals = append(vals, "?")
+				}
+			}
+			if len(vals) > 0 {
+				parameters = append(parameters, fmt.Sprintf("(%s)", strings.Join(vals, ", ")))
+			}
+		}
+	}
+
+	wrapped := make([]string, len(columns))
+	for i, column := range columns {
+		wrapped[i] = g.Wrap(column, false)
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) values %s", table, strings.Join(wrapped, ", "), strings.Join(parameters, ",")), bindings
+}
+
+// CompileInsertOrIgnore Compile an insert ignore statement into SQL.
+func (g *BaseGrammar) CompileInsertOrIgnore(query *query.Query, values []map[string]interface{}) (string, []interface{}) {
+	sql, bindings := g.CompileInsert(query, values)
+	return sql + " ON CONFLICT DO NOTHING", bindings
+}
+
+// CompileUpsert Compile an "upsert" statement into SQL. Rows conflicting
+// with an existing one on the uniqueBy columns update the update columns
+// with the values that were to be inserted.
+func (g *BaseGrammar) CompileUpsert(query *query.Query, values []map[string]interface{}, uniqueBy []string, update []string) (string, []interface{}) {
+	sql, bindings := g.CompileInsert(query, values)
+
+	var unique, columns []string
+	for _, column := range uniqueBy {
+		unique = append(unique, g.Wrap(column, false))
+	}
+	for _, column := range update {
+		columns = append(columns, g.Wrap(column, false)+" = excluded."+g.Wrap(column, false))
+	}
+
+	return sql + " ON CONFLICT (" + strings.Join(unique, ", ") + ") DO UPDATE SET " + strings.Join(columns, ", "), bindings
+}
+
+// CompileInsertGetId Compile an insert and get ID statement into SQL.
+func (g *BaseGrammar) CompileInsertGetId(query *query.Query, values map[string]interface{}, sequence string) (string, []interface{}) {
+	return g.CompileInsert(query, []map[string]interface{}{values})
+}
+
+// SupportsReturning Determine if inserted ids are read back through a RETURNING clause.
+func (g *BaseGrammar) SupportsReturning() bool {
+	return false
+}
+
+// SupportsLastInsertId Determine if the driver reports the id of an inserted row.
+func (g *BaseGrammar) SupportsLastInsertId() bool {
+	return true
+}
+
+// CompileUpdate Compile an update statement into SQL.
+func (g *BaseGrammar) CompileUpdate(query *query.Query, values map[string]interface{}) string {
+	table := g.WrapTable(query.From)
+	columns := g.compileUpdateColumns(values)
+
+	joins := ""
+	if len(query.Joins) > 0 {
+		joins = " " + g.compileJoins(query, query.Joins)
+	}
+
+	where := g.compileWheres(query)
+
+	sql := strings.TrimSpace(fmt.Sprintf("UPDATE %s%s SET %s %s", table, joins, columns, where))
+
+	if len(query.Orders) > 0 {
+		sql = sql + " " + g.compileOrders(query, query.Orders)
+	}
+
+	if query.Limit > 0 {
+		sql = sql + " " + g.compileLimit(query, query.Limit)
+	}
+
+	return sql
+}
+
+// compileUpdateColumns Compile all of the columns for an update statement.
+func (g *BaseGrammar) compileUpdateColumns(values map[string]interface{}) string {
+	var columns []string
+
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		columns = append(columns, fmt.Sprintf("%s = %s", g.Wrap(key, false), g.Parameter(values[key])))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// CompileDelete Compile a delete statement into SQL.
+func (g *BaseGrammar) CompileDelete(query *query.Query) string {
+	table := g.WrapTable(query.From)
+	where := ""
+
+	if len(query.Wheres) > 0 {
+		where = g.compileWheres(query)
+	}
+
+	if len(query.Joins) > 0 {
+		return g.compileDeleteWithJoins(query, table, where)
+	} else {
+		return g.compileDeleteWithoutJoins(query, table, where)
+	}
+}
+
+// compileDeleteWithoutJoins Compile a delete query that does not use joins.
+func (g *BaseGrammar) compileDeleteWithoutJoins(query *query.Query, table string, where string) string {
+	sql := strings.TrimSpace(fmt.Sprintf("DELETE FROM %s %s", table, where))
+
+	if len(query.Orders) > 0 {
+		sql = sql + " " + g.compileOrders(query, query.Orders)
+	}
+
+	if query.Limit > 0 {
+		sql = sql + " " + g.compileLimit(query, query.Limit)
+	}
+
+	return sql
+}
+
+// compileDeleteWithJoins Compile a delete query that uses joins.
+func (g *BaseGrammar) compileDeleteWithJoins(query *query.Query, table string, where string) string {
+	joins := " " + g.compileJoins(query, query.Joins)
+
+	alias := table
+
+	if strings.Contains(strings.ToLower(table), " as ") {
+		alias = strings.Split(table, " as ")[1]
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("DELETE %s FROM %s%s %s", alias, table, joins, where))
+}
+
+func removeLeadingBoolean(value string) string {
+	reg := regexp.MustCompile(`(?i:and |or )`)
+	n := 0
+	b := reg.ReplaceAllFunc([]byte(value), func(bytes []byte) []byte {
+		n = n + 1
+		if n > 1 {
+			return bytes
+		}
+		return []byte("")
+	})
+	return string(b)
+	// return reg.ReplaceAllString(value, "")
 }
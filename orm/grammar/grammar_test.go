@@ -0,0 +1,258 @@
+package grammar
+
+import (
+	"testing"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+func TestCompileSelectQuoting(t *testing.T) {
+	q := &query.Query{
+		From: "users",
+		Wheres: []*query.Where{
+			{Type: "Basic", Column: "users.name", Operator: "=", Boolean: "and"},
+			{Type: "In", Column: "id", Values: []interface{}{1, 2}, Boolean: "and"},
+		},
+	}
+
+	tests := []struct {
+		grammar Grammar
+		want    string
+	}{
+		{NewMySqlGrammar(), "SELECT * FROM `users` WHERE `users`.`name` = ? and `id` IN (?, ?)"},
+		{NewSqliteGrammar(), `SELECT * FROM "users" WHERE "users"."name" = ? and "id" IN (?, ?)`},
+		{NewPostgresGrammar(), `SELECT * FROM "users" WHERE "users"."name" = $1 and "id" IN ($2, $3)`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.grammar.CompileSelect(q); got != tt.want {
+			t.Errorf("invalid select sql: got:%s want:%s", got, tt.want)
+		}
+	}
+}
+
+func TestCompileInsertGetId(t *testing.T) {
+	q := &query.Query{From: "users"}
+	values := map[string]interface{}{"name": "uno"}
+
+	tests := []struct {
+		grammar      Grammar
+		want         string
+		lastInsertId bool
+	}{
+		{NewMySqlGrammar(), "INSERT INTO `users` (`name`) values (?)", true},
+		{NewSqliteGrammar(), `INSERT INTO "users" ("name") values (?) RETURNING "id"`, true},
+		{NewPostgresGrammar(), `INSERT INTO "users" ("name") values ($1) RETURNING "id"`, false},
+	}
+
+	for _, tt := range tests {
+		got, bindings := tt.grammar.CompileInsertGetId(q, values, "id")
+		if got != tt.want {
+			t.Errorf("invalid insert sql: got:%s want:%s", got, tt.want)
+		}
+		if tt.grammar.SupportsLastInsertId() != tt.lastInsertId {
+			t.Errorf("invalid last insert id support: got:%v want:%v", !tt.lastInsertId, tt.lastInsertId)
+		}
+		if len(bindings) != 1 || bindings[0] != "uno" {
+			t.Errorf("invalid insert bindings: got:%v", bindings)
+		}
+	}
+}
+
+func TestNumberParameters(t *testing.T) {
+	got := numberParameters(`SELECT '?', "a?" FROM t WHERE a = ? AND b = 'it''s?' AND c = ?`)
+	want := `SELECT '?', "a?" FROM t WHERE a = $1 AND b = 'it''s?' AND c = $2`
+	if got != want {
+		t.Errorf("invalid numbered sql: got:%s want:%s", got, want)
+	}
+}
+
+func TestCompileUnion(t *testing.T) {
+	q := &query.Query{
+		From: "users",
+		Unions: []*query.Union{
+			{Query: &query.Query{From: "admins"}, All: true},
+			{Query: &query.Query{From: "guests", Wheres: []*query.Where{
+				{Type: "Basic", Column: "active", Operator: "=", Boolean: "and"},
+			}}},
+		},
+		UnionOrders: []*query.UnionOrder{{Column: "name", Direction: "ASC"}},
+		UnionLimit:  10,
+	}
+
+	tests := []struct {
+		grammar Grammar
+		want    string
+	}{
+		{NewMySqlGrammar(), "(SELECT * FROM `users`) UNION ALL (SELECT * FROM `admins`) UNION (SELECT * FROM `guests` WHERE `active` = ?) ORDER BY `name` ASC LIMIT 10"},
+		{NewSqliteGrammar(), `SELECT * FROM (SELECT * FROM "users") UNION ALL SELECT * FROM (SELECT * FROM "admins") UNION SELECT * FROM (SELECT * FROM "guests" WHERE "active" = ?) ORDER BY "name" ASC LIMIT 10`},
+		{NewPostgresGrammar(), `(SELECT * FROM "users") UNION ALL (SELECT * FROM "admins") UNION (SELECT * FROM "guests" WHERE "active" = $1) ORDER BY "name" ASC LIMIT 10`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.grammar.CompileSelect(q); got != tt.want {
+			t.Errorf("invalid union sql: got:%s want:%s", got, tt.want)
+		}
+	}
+
+	q.Aggregate = &query.Aggregate{Function: "COUNT", Columns: []string{"*"}}
+	want := "SELECT COUNT(*) AS aggregate FROM ((SELECT * FROM `users`) UNION ALL (SELECT * FROM `admins`) UNION (SELECT * FROM `guests` WHERE `active` = ?) ORDER BY `name` ASC LIMIT 10) AS `temp_table`"
+	if got := NewMySqlGrammar().CompileSelect(q); got != want {
+		t.Errorf("invalid union aggregate sql: got:%s want:%s", got, want)
+	}
+}
+
+func TestCompileLock(t *testing.T) {
+	tests := []struct {
+		grammar Grammar
+		lock    *query.Lock
+		want    string
+	}{
+		{NewMySqlGrammar(), &query.Lock{}, "SELECT * FROM `jobs` FOR UPDATE"},
+		{NewMySqlGrammar(), &query.Lock{Option: query.SkipLocked}, "SELECT * FROM `jobs` FOR UPDATE SKIP LOCKED"},
+		{NewMySqlGrammar(), &query.Lock{Shared: true}, "SELECT * FROM `jobs` LOCK IN SHARE MODE"},
+		{NewMySqlGrammar(), &query.Lock{Shared: true, Option: query.NoWait}, "SELECT * FROM `jobs` FOR SHARE NOWAIT"},
+		{NewPostgresGrammar(), &query.Lock{Option: query.NoWait}, "SELECT * FROM \"jobs\" FOR UPDATE NOWAIT"},
+		{NewPostgresGrammar(), &query.Lock{Shared: true}, "SELECT * FROM \"jobs\" FOR SHARE"},
+		{NewSqliteGrammar(), &query.Lock{Option: query.SkipLocked}, "SELECT * FROM \"jobs\""},
+	}
+
+	for _, tt := range tests {
+		q := &query.Query{From: "jobs", Lock: tt.lock}
+		if got := tt.grammar.CompileSelect(q); got != tt.want {
+			t.Errorf("invalid lock sql: got:%s want:%s", got, tt.want)
+		}
+	}
+}
+
+func TestCompileUpsert(t *testing.T) {
+	q := &query.Query{From: "users"}
+	values := []map[string]interface{}{{"email": "uno@example.com", "name": "uno"}}
+
+	tests := []struct {
+		grammar Grammar
+		want    string
+		ignore  string
+	}{
+		{
+			NewMySqlGrammar(),
+			"INSERT INTO `users` (`email`, `name`) values (?, ?) ON DUPLICATE KEY UPDATE `name` = values(`name`)",
+			"INSERT IGNORE INTO `users` (`email`, `name`) values (?, ?)",
+		},
+		{
+			NewSqliteGrammar(),
+			`INSERT INTO "users" ("email", "name") values (?, ?) ON CONFLICT ("email") DO UPDATE SET "name" = excluded."name"`,
+			`INSERT INTO "users" ("email", "name") values (?, ?) ON CONFLICT DO NOTHING`,
+		},
+		{
+			NewPostgresGrammar(),
+			`INSERT INTO "users" ("email", "name") values ($1, $2) ON CONFLICT ("email") DO UPDATE SET "name" = excluded."name"`,
+			`INSERT INTO "users" ("email", "name") values ($1, $2) ON CONFLICT DO NOTHING`,
+		},
+	}
+
+	for _, tt := range tests {
+		if got, _ := tt.grammar.CompileUpsert(q, values, []string{"email"}, []string{"name"}); got != tt.want {
+			t.Errorf("invalid upsert sql: got:%s want:%s", got, tt.want)
+		}
+		if got, _ := tt.grammar.CompileInsertOrIgnore(q, values); got != tt.ignore {
+			t.Errorf("invalid insert ignore sql: got:%s want:%s", got, tt.ignore)
+		}
+	}
+}
+
+func TestTablePrefix(t *testing.T) {
+	g := NewSqliteGrammar()
+	g.SetTablePrefix("app_")
+
+	q := &query.Query{
+		Columns: []interface{}{"users.name", query.Expr("COUNT(*) as posts")},
+		From:    "users",
+		Joins: []*query.Join{{
+			Type:  "inner",
+			Table: "posts",
+			Query: &query.Query{JoinClause: true, Wheres: []*query.Where{
+				{Type: "Column", First: "posts.user_id", Operator: "=", Second: "users.id", Boolean: "and"},
+			}},
+		}},
+		Wheres: []*query.Where{
+			{Type: "Basic", Column: "users.active", Operator: "=", Boolean: "and"},
+		},
+		Groups: []interface{}{"users.name"},
+		Orders: []*query.Order{{Column: "users.name", Direction: "ASC"}},
+	}
+
+	want := `SELECT "app_users"."name", COUNT(*) as posts FROM "app_users" inner JOIN "app_posts" ON "app_posts"."user_id" = "app_users"."id" WHERE "app_users"."active" = ? GROUP BY "app_users"."name" ORDER BY "app_users"."name" ASC`
+	if got := g.CompileSelect(q); got != want {
+		t.Errorf("invalid prefixed select: got:%s want:%s", got, want)
+	}
+
+	update := g.CompileUpdate(&query.Query{From: "users"}, map[string]interface{}{"name": "uno"})
+	if want := `UPDATE "app_users" SET "name" = ?`; update != want {
+		t.Errorf("invalid prefixed update: got:%s want:%s", update, want)
+	}
+
+	del := g.CompileDelete(&query.Query{From: "users"})
+	if want := `DELETE FROM "app_users"`; del != want {
+		t.Errorf("invalid prefixed delete: got:%s want:%s", del, want)
+	}
+
+	sub := g.CompileSelect(&query.Query{
+		Columns: []interface{}{"s.name"},
+		From:    "s",
+		FromSub: &query.Sub{Query: &query.Query{From: "users"}, As: "s"},
+		Wheres: []*query.Where{
+			{Type: "Basic", Column: "s.id", Operator: "=", Boolean: "and"},
+		},
+	})
+	if want := `SELECT "app_s"."name" FROM (SELECT * FROM "app_users") AS "app_s" WHERE "app_s"."id" = ?`; sub != want {
+		t.Errorf("invalid prefixed from sub: got:%s want:%s", sub, want)
+	}
+}
+
+func TestWrapIdentifiers(t *testing.T) {
+	g := NewMySqlGrammar()
+	g.SetTablePrefix("app_")
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"order", "`order`"},
+		{"na`me", "`na``me`"},
+		{"users.*", "`app_users`.*"},
+		{"u.name as username", "`app_u`.`name` as `username`"},
+		{"group AS g", "`group` as `g`"},
+		{"db.users.name", "`db`.`app_users`.`name`"},
+	}
+
+	for _, tt := range tests {
+		if got := g.Wrap(tt.value, false); got != tt.want {
+			t.Errorf("invalid wrapped value: got:%s want:%s", got, tt.want)
+		}
+	}
+
+	for table, want := range map[string]string{
+		"users as u":    "`app_users` as `app_u`",
+		"db.users":      "`db`.`app_users`",
+		"db.users as u": "`db`.`app_users` as `app_u`",
+	} {
+		if got := g.WrapTable(table); got != want {
+			t.Errorf("invalid wrapped table: got:%s want:%s", got, want)
+		}
+	}
+	if got, want := g.CompileSelect(&query.Query{From: "db.users"}), "SELECT * FROM `db`.`app_users`"; got != want {
+		t.Errorf("invalid qualified table select: got:%s want:%s", got, want)
+	}
+
+	q := &query.Query{
+		Columns: []interface{}{"order", query.Expr("SUM(total) AS total")},
+		From:    "orders as o",
+		Groups:  []interface{}{"o.group", query.Expr("DATE(created_at)")},
+		Orders:  []*query.Order{{Column: "o.order", Direction: "DESC"}},
+	}
+	want := "SELECT `order`, SUM(total) AS total FROM `app_orders` as `app_o` GROUP BY `app_o`.`group`, DATE(created_at) ORDER BY `app_o`.`order` DESC"
+	if got := g.CompileSelect(q); got != want {
+		t.Errorf("invalid wrapped select: got:%s want:%s", got, want)
+	}
+}
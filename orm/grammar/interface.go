@@ -9,12 +9,33 @@ type Grammar interface {
 	// CompileInsert Compile an insert statement into SQL.
 	CompileInsert(query *query.Query, values []map[string]interface{}) (string, []interface{})
 
+	// CompileInsertOrIgnore Compile an insert ignore statement into SQL.
+	CompileInsertOrIgnore(query *query.Query, values []map[string]interface{}) (string, []interface{})
+
+	// CompileUpsert Compile an "upsert" statement into SQL.
+	CompileUpsert(query *query.Query, values []map[string]interface{}, uniqueBy []string, update []string) (string, []interface{})
+
+	// CompileInsertGetId Compile an insert and get ID statement into SQL.
+	CompileInsertGetId(query *query.Query, values map[string]interface{}, sequence string) (string, []interface{})
+
+	// SupportsReturning Determine if inserted ids are read back through a RETURNING clause.
+	SupportsReturning() bool
+
+	// SupportsLastInsertId Determine if the driver reports the id of an inserted row.
+	SupportsLastInsertId() bool
+
 	// CompileUpdate Compile an update statement into SQL.
 	CompileUpdate(query *query.Query, values map[string]interface{}) string
 
 	// CompileDelete Compile a delete statement into SQL.
 	CompileDelete(query *query.Query) string
 
+	// GetTablePrefix Get the prefix of the table names.
+	GetTablePrefix() string
+
+	// SetTablePrefix Set the prefix of the table names.
+	SetTablePrefix(prefix string)
+
 	// PrepareBindingsForUpdate Prepare the bindings for an update statement.
 	PrepareBindingsForUpdate(bindings map[string][]interface{}, values map[string]interface{}) []interface{}
 }
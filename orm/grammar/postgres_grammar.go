@@ -0,0 +1,106 @@
+package grammar
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+type PostgresGrammar struct {
+	BaseGrammar
+}
+
+// NewPostgresGrammar Create a new PostgreSQL query grammar.
+func NewPostgresGrammar() *PostgresGrammar {
+	g := &PostgresGrammar{}
+	g.dialect = g
+	return g
+}
+
+// wrapValue Wrap a single string in double quotes.
+func (g *PostgresGrammar) wrapValue(value string) string {
+	if value != "*" {
+		return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+	}
+	return value
+}
+
+// CompileSelect Compile a select query into SQL.
+func (g *PostgresGrammar) CompileSelect(query *query.Query) string {
+	return numberParameters(g.BaseGrammar.CompileSelect(query))
+}
+
+// CompileInsert Compile an insert statement into SQL.
+func (g *PostgresGrammar) CompileInsert(query *query.Query, values []map[string]interface{}) (string, []interface{}) {
+	sql, bindings := g.BaseGrammar.CompileInsert(query, values)
+	return numberParameters(sql), bindings
+}
+
+// CompileInsertOrIgnore Compile an insert ignore statement into SQL.
+func (g *PostgresGrammar) CompileInsertOrIgnore(query *query.Query, values []map[string]interface{}) (string, []interface{}) {
+	sql, bindings := g.BaseGrammar.CompileInsertOrIgnore(query, values)
+	return numberParameters(sql), bindings
+}
+
+// CompileUpsert Compile an "upsert" statement into SQL.
+func (g *PostgresGrammar) CompileUpsert(query *query.Query, values []map[string]interface{}, uniqueBy []string, update []string) (string, []interface{}) {
+	sql, bindings := g.BaseGrammar.CompileUpsert(query, values, uniqueBy, update)
+	return numberParameters(sql), bindings
+}
+
+// CompileInsertGetId Compile an insert and get ID statement into SQL.
+func (g *PostgresGrammar) CompileInsertGetId(query *query.Query, values map[string]interface{}, sequence string) (string, []interface{}) {
+	sql, bindings := g.CompileInsert(query, []map[string]interface{}{values})
+	return sql + " RETURNING " + g.Wrap(sequence, false), bindings
+}
+
+// SupportsReturning Determine if inserted ids are read back through a RETURNING clause.
+func (g *PostgresGrammar) SupportsReturning() bool {
+	return true
+}
+
+// SupportsLastInsertId Determine if the driver reports the id of an inserted
+// row, which the Postgres drivers do not.
+func (g *PostgresGrammar) SupportsLastInsertId() bool {
+	return false
+}
+
+// CompileUpdate Compile an update statement into SQL.
+func (g *PostgresGrammar) CompileUpdate(query *query.Query, values map[string]interface{}) string {
+	return numberParameters(g.BaseGrammar.CompileUpdate(query, values))
+}
+
+// CompileDelete Compile a delete statement into SQL.
+func (g *PostgresGrammar) CompileDelete(query *query.Query) string {
+	return numberParameters(g.BaseGrammar.CompileDelete(query))
+}
+
+// numberParameters Replace the "?" place-holders of a compiled statement with
+// the "$1", "$2", ... form PostgreSQL expects. Question marks inside quoted
+// strings and identifiers are left untouched.
+func numberParameters(sql string) string {
+	var (
+		b     strings.Builder
+		quote rune
+		n     int
+	)
+
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package grammar
+
+import (
+	"strings"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+type SqliteGrammar struct {
+	BaseGrammar
+}
+
+// NewSqliteGrammar Create a new SQLite query grammar.
+func NewSqliteGrammar() *SqliteGrammar {
+	g := &SqliteGrammar{}
+	g.dialect = g
+	return g
+}
+
+// wrapValue Wrap a single string in double quotes.
+func (g *SqliteGrammar) wrapValue(value string) string {
+	if value != "*" {
+		return `"` + strings.Replace(value, `"`, `""`, -1) + `"`
+	}
+	return value
+}
+
+// wrapUnion Wrap a select statement taking part in a union. SQLite does
+// not allow parentheses around the selects of a compound statement.
+func (g *SqliteGrammar) wrapUnion(sql string) string {
+	return "SELECT * FROM (" + sql + ")"
+}
+
+// compileLock Compile the lock into SQL. SQLite locks the whole database
+// for a write transaction, so row locks are left out.
+func (g *SqliteGrammar) compileLock(query *query.Query, lock *query.Lock) string {
+	return ""
+}
+
+// CompileInsertGetId Compile an insert and get ID statement into SQL.
+func (g *SqliteGrammar) CompileInsertGetId(query *query.Query, values map[string]interface{}, sequence string) (string, []interface{}) {
+	sql, bindings := g.CompileInsert(query, []map[string]interface{}{values})
+	return sql + " RETURNING " + g.Wrap(sequence, false), bindings
+}
+
+// SupportsReturning Determine if inserted ids are read back through a RETURNING clause.
+func (g *SqliteGrammar) SupportsReturning() bool {
+	return true
+}
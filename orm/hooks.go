@@ -0,0 +1,136 @@
+package orm
+
+import "reflect"
+
+// The hooks a model can implement to run code around its lifecycle. Create
+// calls BeforeSave, BeforeCreate, AfterCreate and AfterSave, Save on a stored
+// model calls BeforeSave, BeforeUpdate, AfterUpdate and AfterSave, Destroy
+// calls BeforeDelete and AfterDelete, and Builder.Get calls AfterFind on every
+// model it reads. The connection passed in is the one running the operation,
+// so hooks can write through it within the same transaction.
+
+type BeforeSave interface {
+	BeforeSave(conn *Connection) error
+}
+
+type AfterSave interface {
+	AfterSave(conn *Connection) error
+}
+
+type BeforeCreate interface {
+	BeforeCreate(conn *Connection) error
+}
+
+type AfterCreate interface {
+	AfterCreate(conn *Connection) error
+}
+
+type BeforeUpdate interface {
+	BeforeUpdate(conn *Connection) error
+}
+
+type AfterUpdate interface {
+	AfterUpdate(conn *Connection) error
+}
+
+type BeforeDelete interface {
+	BeforeDelete(conn *Connection) error
+}
+
+type AfterDelete interface {
+	AfterDelete(conn *Connection) error
+}
+
+type AfterFind interface {
+	AfterFind(conn *Connection) error
+}
+
+const (
+	beforeSave   = "BeforeSave"
+	afterSave    = "AfterSave"
+	beforeCreate = "BeforeCreate"
+	afterCreate  = "AfterCreate"
+	beforeUpdate = "BeforeUpdate"
+	afterUpdate  = "AfterUpdate"
+	beforeDelete = "BeforeDelete"
+	afterDelete  = "AfterDelete"
+	afterFind    = "AfterFind"
+)
+
+// callHooks Call the given hooks the model implements, in order, stopping
+// at the first error.
+func callHooks(model interface{}, conn *Connection, hooks ...string) error {
+	for _, hook := range hooks {
+		var err error
+
+		switch hook {
+		case beforeSave:
+			if h, ok := model.(BeforeSave); ok {
+				err = h.BeforeSave(conn)
+			}
+		case afterSave:
+			if h, ok := model.(AfterSave); ok {
+				err = h.AfterSave(conn)
+			}
+		case beforeCreate:
+			if h, ok := model.(BeforeCreate); ok {
+				err = h.BeforeCreate(conn)
+			}
+		case afterCreate:
+			if h, ok := model.(AfterCreate); ok {
+				err = h.AfterCreate(conn)
+			}
+		case beforeUpdate:
+			if h, ok := model.(BeforeUpdate); ok {
+				err = h.BeforeUpdate(conn)
+			}
+		case afterUpdate:
+			if h, ok := model.(AfterUpdate); ok {
+				err = h.AfterUpdate(conn)
+			}
+		case beforeDelete:
+			if h, ok := model.(BeforeDelete); ok {
+				err = h.BeforeDelete(conn)
+			}
+		case afterDelete:
+			if h, ok := model.(AfterDelete); ok {
+				err = h.AfterDelete(conn)
+			}
+		case afterFind:
+			if h, ok := model.(AfterFind); ok {
+				err = h.AfterFind(conn)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withHooks Run a write operation of the model. When the model implements
+// any of the given hooks the operation runs in a transaction, so that an
+// error returned by an After hook also undoes the write.
+func (c *Connection) withHooks(model interface{}, hooks []string, callback func(conn *Connection) error) error {
+	for _, hook := range hooks {
+		if reflect.TypeOf(model).Implements(hookTypes[hook]) {
+			return c.Transaction(callback)
+		}
+	}
+
+	return callback(c)
+}
+
+var hookTypes = map[string]reflect.Type{
+	beforeSave:   reflect.TypeOf((*BeforeSave)(nil)).Elem(),
+	afterSave:    reflect.TypeOf((*AfterSave)(nil)).Elem(),
+	beforeCreate: reflect.TypeOf((*BeforeCreate)(nil)).Elem(),
+	afterCreate:  reflect.TypeOf((*AfterCreate)(nil)).Elem(),
+	beforeUpdate: reflect.TypeOf((*BeforeUpdate)(nil)).Elem(),
+	afterUpdate:  reflect.TypeOf((*AfterUpdate)(nil)).Elem(),
+	beforeDelete: reflect.TypeOf((*BeforeDelete)(nil)).Elem(),
+	afterDelete:  reflect.TypeOf((*AfterDelete)(nil)).Elem(),
+	afterFind:    reflect.TypeOf((*AfterFind)(nil)).Elem(),
+}
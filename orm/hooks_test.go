@@ -0,0 +1,108 @@
+package orm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errHookFailed = errors.New("hook failed")
+
+type testHookUser struct {
+	ID        int64 `torm:"primary_key"`
+	Name      string
+	Greeting  string `torm:"-"`
+	calls     []string
+	failAfter bool
+}
+
+func (u *testHookUser) TableName() string {
+	return "users"
+}
+
+func (u *testHookUser) BeforeSave(conn *Connection) error {
+	u.calls = append(u.calls, "BeforeSave")
+	u.Name = strings.ToLower(u.Name)
+	return nil
+}
+
+func (u *testHookUser) BeforeCreate(conn *Connection) error {
+	u.calls = append(u.calls, "BeforeCreate")
+	return nil
+}
+
+func (u *testHookUser) AfterCreate(conn *Connection) error {
+	u.calls = append(u.calls, "AfterCreate")
+	if u.failAfter {
+		return errHookFailed
+	}
+	return nil
+}
+
+func (u *testHookUser) AfterSave(conn *Connection) error {
+	u.calls = append(u.calls, "AfterSave")
+	return nil
+}
+
+func (u *testHookUser) BeforeDelete(conn *Connection) error {
+	if u.Name == "admin" {
+		return errHookFailed
+	}
+	return nil
+}
+
+func (u *testHookUser) AfterFind(conn *Connection) error {
+	u.Greeting = "hello " + u.Name
+	return nil
+}
+
+func TestCreateCallsHooksInOrder(t *testing.T) {
+	conn := newTestConnection(t)
+
+	user := &testHookUser{Name: "UNO"}
+	if err := conn.Create(user); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "BeforeSave,BeforeCreate,AfterCreate,AfterSave"
+	if got := strings.Join(user.calls, ","); got != want {
+		t.Errorf("invalid hook calls: got:%s want:%s", got, want)
+	}
+
+	var found testHookUser
+	if err := conn.Table("users").Where("id", user.ID).First(&found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "uno" || found.Greeting != "hello uno" {
+		t.Errorf("invalid found user: got:%+v", found)
+	}
+}
+
+func TestAfterHookErrorRollsBack(t *testing.T) {
+	conn := newTestConnection(t)
+
+	user := &testHookUser{Name: "uno", failAfter: true}
+	if err := conn.Create(user); err != errHookFailed {
+		t.Fatalf("invalid create error: got:%v want:%v", err, errHookFailed)
+	}
+
+	if got := countUsers(t, conn); got != 0 {
+		t.Errorf("invalid user count: got:%d want:%d", got, 0)
+	}
+}
+
+func TestBeforeHookErrorAborts(t *testing.T) {
+	conn := newTestConnection(t)
+
+	user := &testHookUser{Name: "admin"}
+	if err := conn.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Destroy(user); err != errHookFailed {
+		t.Fatalf("invalid destroy error: got:%v want:%v", err, errHookFailed)
+	}
+
+	if got := countUsers(t, conn); got != 1 {
+		t.Errorf("invalid user count: got:%d want:%d", got, 1)
+	}
+}
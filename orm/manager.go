@@ -2,27 +2,139 @@ package orm
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/glugox/unogo/config"
 )
 
 type Config struct {
 	Driver string
 	Prefix string
-	Dsn    string
+
+	// Dsn The data source name of the database, the writer when Read is set.
+	Dsn string
+
+	// Read The data source names of the read replicas. Select statements
+	// run on a random one of them, everything else on the writer.
+	Read []string
+
+	// Sticky Read from the writer once a connection made by WithContext,
+	// such as the one of a request, has written, so that it reads its own
+	// writes while the replicas catch up.
+	Sticky bool
+
+	// The connection pool settings of every database, see sql.DB. Zero
+	// values keep the database/sql defaults.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
-// Database manager.
+// Database manager. It makes the configured connections on first use and
+// caches them, so every name maps to a single pool.
 type Manager struct {
+	sync.Mutex
+	defaultName string
 	configs     map[string]Config
-	connections map[string]Connection
+	connections map[string]*Connection
+}
+
+// NewManager Create a database manager for the connections of the config.
+func NewManager(c config.DatabaseConfig) (*Manager, error) {
+	m := &Manager{
+		defaultName: c.Connection,
+		configs:     make(map[string]Config),
+		connections: make(map[string]*Connection),
+	}
+
+	for name, connection := range c.Connections {
+		cfg, err := newConfig(connection)
+		if err != nil {
+			return nil, fmt.Errorf("database [%s]: %w", name, err)
+		}
+		cfg.MaxOpenConns = c.MaxOpenConns
+		cfg.MaxIdleConns = c.MaxIdleConns
+		cfg.ConnMaxLifetime = c.ConnMaxLifetime
+		m.configs[name] = cfg
+	}
+
+	return m, nil
+}
+
+// Connection Get a database connection instance, the default one when no
+// name is given.
+func (m *Manager) Connection(name ...string) (*Connection, error) {
+	if len(name) > 0 && name[0] != "" {
+		return m.Connect(name[0])
+	}
+	return m.Connect(m.GetDefaultConnection())
 }
 
 // Connect Get a database connection instance.
 func (m *Manager) Connect(name string) (*Connection, error) {
+	m.Lock()
+	defer m.Unlock()
+
 	if conn, ok := m.connections[name]; ok {
-		return &conn, nil
+		return conn, nil
+	}
+
+	conn, err := m.make(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.connections == nil {
+		m.connections = make(map[string]*Connection)
+	}
+	m.connections[name] = conn
+
+	return conn, nil
+}
+
+// GetDefaultConnection Get the name of the default connection.
+func (m *Manager) GetDefaultConnection() string {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.defaultName
+}
+
+// SetDefaultConnection Set the name of the default connection.
+func (m *Manager) SetDefaultConnection(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.defaultName = name
+}
+
+// AddConnection Register the config of a connection.
+func (m *Manager) AddConnection(name string, cfg Config) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.configs == nil {
+		m.configs = make(map[string]Config)
 	}
+	m.configs[name] = cfg
+}
+
+// Close Close every connection made by the manager.
+func (m *Manager) Close() error {
+	m.Lock()
+	defer m.Unlock()
 
-	return m.make(name)
+	var err error
+	for name, conn := range m.connections {
+		if cerr := conn.Close(); err == nil {
+			err = cerr
+		}
+		delete(m.connections, name)
+	}
+	return err
 }
 
 func (m *Manager) make(name string) (*Connection, error) {
@@ -33,3 +145,69 @@ func (m *Manager) make(name string) (*Connection, error) {
 
 	return Open(config)
 }
+
+// newConfig Get the connection config of a configured database, building
+// its data source names from the host, port, database and charset settings.
+func newConfig(c config.Connection) (Config, error) {
+	dsn, err := newDsn(c, c.Host)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Driver: c.Driver,
+		Prefix: c.Prefix,
+		Dsn:    dsn,
+		Sticky: c.Sticky,
+	}
+
+	for _, host := range c.Read {
+		dsn, err := newDsn(c, host)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Read = append(cfg.Read, dsn)
+	}
+
+	return cfg, nil
+}
+
+// newDsn Build the data source name of a database on the given host.
+func newDsn(c config.Connection, host string) (string, error) {
+	switch c.Driver {
+	case "mysql":
+		params := url.Values{"parseTime": {"true"}}
+		if c.Charset != "" {
+			params.Set("charset", c.Charset)
+		}
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s?%s",
+			c.Username, c.Password, net.JoinHostPort(host, defaultPort(c.Port, "3306")), c.Database, params.Encode()), nil
+	case "postgres", "pgx":
+		params := url.Values{}
+		if c.SslMode != "" {
+			params.Set("sslmode", c.SslMode)
+		}
+		if c.Charset != "" {
+			params.Set("client_encoding", c.Charset)
+		}
+		u := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.Username, c.Password),
+			Host:     net.JoinHostPort(host, defaultPort(c.Port, "5432")),
+			Path:     "/" + c.Database,
+			RawQuery: params.Encode(),
+		}
+		return u.String(), nil
+	case "sqlite3", "sqlite":
+		return c.Database, nil
+	}
+
+	return "", fmt.Errorf("database driver [%s] not supported", c.Driver)
+}
+
+func defaultPort(port string, value string) string {
+	if port == "" {
+		return value
+	}
+	return port
+}
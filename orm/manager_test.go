@@ -0,0 +1,92 @@
+package orm
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/glugox/unogo/config"
+)
+
+func TestNewDsn(t *testing.T) {
+	tests := []struct {
+		connection config.Connection
+		want       string
+	}{
+		{
+			config.Connection{Driver: "mysql", Host: "db", Database: "app", Username: "root", Password: "secret", Charset: "utf8mb4"},
+			"root:secret@tcp(db:3306)/app?charset=utf8mb4&parseTime=true",
+		},
+		{
+			config.Connection{Driver: "postgres", Host: "db", Port: "6432", Database: "app", Username: "uno", Password: "p@ss"},
+			"postgres://uno:p%40ss@db:6432/app",
+		},
+		{
+			config.Connection{Driver: "pgx", Host: "db", Database: "app", Username: "uno", Password: "p@ss", Charset: "UTF8", SslMode: "require"},
+			"postgres://uno:p%40ss@db:5432/app?client_encoding=UTF8&sslmode=require",
+		},
+		{
+			config.Connection{Driver: "sqlite3", Database: "app.db"},
+			"app.db",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := newDsn(tt.connection, tt.connection.Host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("invalid dsn: got:%s want:%s", got, tt.want)
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := NewManager(config.DatabaseConfig{
+		Connection: "main",
+		Connections: map[string]config.Connection{
+			"main":  {Driver: "sqlite3", Database: filepath.Join(dir, "main.db")},
+			"stats": {Driver: "sqlite3", Database: filepath.Join(dir, "stats.db")},
+		},
+		MaxOpenConns: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	conns := make([]*Connection, 8)
+	var wg sync.WaitGroup
+	for i := range conns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conns[i], _ = m.Connection()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, conn := range conns {
+		if conn == nil || conn != conns[0] {
+			t.Fatalf("invalid cached connection: got:%p want:%p", conn, conns[0])
+		}
+	}
+	if got := conns[0].DB.Stats().MaxOpenConnections; got != 4 {
+		t.Errorf("invalid max open connections: got:%d want:%d", got, 4)
+	}
+
+	stats, err := m.Connection("stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats == conns[0] {
+		t.Error("invalid named connection: got the default one")
+	}
+
+	if _, err := m.Connection("missing"); err == nil {
+		t.Error("expected an error for a missing connection")
+	}
+}
@@ -2,6 +2,13 @@ package orm
 
 import "github.com/glugox/unogo/orm/field"
 
+// The torm tags marking the timestamp fields of a model.
+const (
+	CreatedAt = "created_at"
+	UpdatedAt = "updated_at"
+	DeletedAt = "deleted_at"
+)
+
 type Model struct {
 	field.IDAttr
 	field.CreatedAtAttr
@@ -0,0 +1,48 @@
+package orm
+
+import (
+	"path/filepath"
+	"testing"
+
+	_ "github.com/glugox/unogo/orm/driver/sqlite"
+)
+
+type testUser struct {
+	ID   int64 `torm:"primary_key"`
+	Name string
+}
+
+func (u *testUser) TableName() string {
+	return "users"
+}
+
+// newTestConnection Open a connection to a fresh SQLite database with a users table.
+func newTestConnection(t *testing.T) *Connection {
+	t.Helper()
+
+	conn, err := Open(Config{
+		Driver: "sqlite3",
+		Dsn:    filepath.Join(t.TempDir(), "orm.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.DB.Close() })
+
+	err = conn.Statement("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func countUsers(t *testing.T, conn *Connection) int {
+	t.Helper()
+
+	var count int
+	if err := conn.Table("users").Count(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
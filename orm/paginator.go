@@ -0,0 +1,474 @@
+package orm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Paginator A page of the results of a query, together with the total
+// number of results. It is JSON serializable, so it can be rendered as it is:
+//
+//	paginator, err := conn.Table("users").Paginate(page, 15, &users)
+//	return uno.Json(paginator.WithPath(request.Url()))
+type Paginator struct {
+	Items       interface{}
+	Total       int64
+	PerPage     uint64
+	CurrentPage uint64
+	LastPage    uint64
+	Path        string
+}
+
+// SimplePaginator A page of the results of a query, without the total number
+// of results, which saves the count query.
+type SimplePaginator struct {
+	Items       interface{}
+	PerPage     uint64
+	CurrentPage uint64
+	HasMore     bool
+	Path        string
+}
+
+// CursorPaginator A page of the results of a query, paged by the values of
+// its ordered columns rather than by offset. NextCursor is the opaque token
+// of the next page, empty on the last page.
+type CursorPaginator struct {
+	Items      interface{}
+	PerPage    uint64
+	Cursor     string
+	NextCursor string
+	Path       string
+}
+
+// Paginate Paginate the query into dest, a pointer to a slice. Pages start
+// at 1, lower pages are read as the first one.
+func (b *Builder) Paginate(page uint64, perPage uint64, dest interface{}) (*Paginator, error) {
+	if perPage == 0 {
+		return nil, errors.New("per page should be greater than zero")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	total, err := b.getCountForPagination()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.forPage(page, perPage, dest); err != nil {
+		return nil, err
+	}
+
+	lastPage := uint64(1)
+	if total > 0 {
+		lastPage = (uint64(total) + perPage - 1) / perPage
+	}
+
+	return &Paginator{
+		Items:       dest,
+		Total:       total,
+		PerPage:     perPage,
+		CurrentPage: page,
+		LastPage:    lastPage,
+	}, nil
+}
+
+// SimplePaginate Paginate the query into dest, a pointer to a slice, only
+// checking whether there are more pages instead of counting all results.
+func (b *Builder) SimplePaginate(page uint64, perPage uint64, dest interface{}) (*SimplePaginator, error) {
+	if perPage == 0 {
+		return nil, errors.New("per page should be greater than zero")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	// One more row is read to know whether there is a next page.
+	count, err := b.Clone().Take(perPage + 1).Skip((page - 1) * perPage).getBatch(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := uint64(count) > perPage
+	if hasMore {
+		truncate(dest, int(perPage))
+	}
+
+	return &SimplePaginator{
+		Items:       dest,
+		PerPage:     perPage,
+		CurrentPage: page,
+		HasMore:     hasMore,
+	}, nil
+}
+
+// CursorPaginate Paginate the query into dest, a pointer to a slice, starting
+// after the row the cursor points to. An empty cursor gets the first page.
+// The query should be ordered by columns that together are unique, and those
+// columns should be selected, as the next cursor is read from them.
+func (b *Builder) CursorPaginate(perPage uint64, cursor string, dest interface{}) (*CursorPaginator, error) {
+	if perPage == 0 {
+		return nil, errors.New("per page should be greater than zero")
+	}
+
+	orders := b.Query.Orders
+	if len(orders) == 0 {
+		return nil, errors.New("cursor pagination requires an ordered query")
+	}
+	for _, order := range orders {
+		if order.Column == "" {
+			return nil, errors.New("cursor pagination only supports ordering by columns")
+		}
+	}
+
+	builder := b.Clone()
+
+	if cursor != "" {
+		values, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		builder.Query.Wheres = groupWheres(builder.Query.Wheres)
+		builder.Where(func(q *Builder) {
+			// (a > ?) or (a = ? and b > ?) ... for the ordered columns a, b...
+			for i, order := range orders {
+				q.OrWhere(func(q *Builder) {
+					for _, previous := range orders[:i] {
+						q.Where(previous.Column, values[columnAlias(previous.Column)])
+					}
+					operator := ">"
+					if strings.EqualFold(order.Direction, "desc") {
+						operator = "<"
+					}
+					q.Where(order.Column, operator, values[columnAlias(order.Column)])
+				})
+			}
+		})
+	}
+
+	count, err := builder.Limit(perPage + 1).getBatch(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := &CursorPaginator{
+		Items:   dest,
+		PerPage: perPage,
+		Cursor:  cursor,
+	}
+
+	if uint64(count) > perPage {
+		truncate(dest, int(perPage))
+
+		values := make(map[string]interface{})
+		for _, order := range orders {
+			alias := columnAlias(order.Column)
+			if values[alias], err = lastValue(dest, alias); err != nil {
+				return nil, err
+			}
+		}
+		if paginator.NextCursor, err = encodeCursor(values); err != nil {
+			return nil, err
+		}
+	}
+
+	return paginator, nil
+}
+
+// getCountForPagination Get the total number of results of the query,
+// leaving the query itself untouched.
+func (b *Builder) getCountForPagination() (int64, error) {
+	var total int64
+	builder := b.CloneWithout("orders", "limit", "offset").CloneWithoutBindings("order", "unionOrder")
+
+	// A grouped or distinct query gets one row for each of its results, so
+	// the rows of the query are counted instead.
+	if len(builder.Query.Groups) > 0 || len(builder.Query.Havings) > 0 || builder.Query.Distinct {
+		builder = NewBuilder(b.Connection, b.grammar).FromSub(builder, "aggregate_table")
+	}
+
+	err := builder.Count(&total)
+
+	return total, err
+}
+
+// forPage Read the given page of the results into dest.
+func (b *Builder) forPage(page uint64, perPage uint64, dest interface{}) error {
+	_, err := b.Clone().Take(perPage).Skip((page - 1) * perPage).getBatch(dest)
+	return err
+}
+
+// WithPath Set the base path the page links are built from.
+func (p *Paginator) WithPath(path string) *Paginator {
+	p.Path = path
+	return p
+}
+
+// Url Get the link to the given page.
+func (p *Paginator) Url(page uint64) string {
+	return pageUrl(p.Path, "page", strconv.FormatUint(page, 10))
+}
+
+// HasMorePages Determine if there are pages after the current one.
+func (p *Paginator) HasMorePages() bool {
+	return p.CurrentPage < p.LastPage
+}
+
+// NextPageUrl Get the link to the next page, empty on the last page.
+func (p *Paginator) NextPageUrl() string {
+	if !p.HasMorePages() {
+		return ""
+	}
+	return p.Url(p.CurrentPage + 1)
+}
+
+// PreviousPageUrl Get the link to the previous page, empty on the first page.
+func (p *Paginator) PreviousPageUrl() string {
+	if p.CurrentPage <= 1 {
+		return ""
+	}
+	return p.Url(p.CurrentPage - 1)
+}
+
+// MarshalJSON Encode the page with its pagination details.
+func (p *Paginator) MarshalJSON() ([]byte, error) {
+	from, to := pageRange(p.CurrentPage, p.PerPage, p.Items)
+
+	return json.Marshal(struct {
+		CurrentPage  uint64      `json:"current_page"`
+		Data         interface{} `json:"data"`
+		FirstPageUrl string      `json:"first_page_url"`
+		From         *uint64     `json:"from"`
+		LastPage     uint64      `json:"last_page"`
+		LastPageUrl  string      `json:"last_page_url"`
+		NextPageUrl  *string     `json:"next_page_url"`
+		Path         string      `json:"path"`
+		PerPage      uint64      `json:"per_page"`
+		PrevPageUrl  *string     `json:"prev_page_url"`
+		To           *uint64     `json:"to"`
+		Total        int64       `json:"total"`
+	}{
+		CurrentPage:  p.CurrentPage,
+		Data:         jsonItems(p.Items),
+		FirstPageUrl: p.Url(1),
+		From:         from,
+		LastPage:     p.LastPage,
+		LastPageUrl:  p.Url(p.LastPage),
+		NextPageUrl:  nullString(p.NextPageUrl()),
+		Path:         p.Path,
+		PerPage:      p.PerPage,
+		PrevPageUrl:  nullString(p.PreviousPageUrl()),
+		To:           to,
+		Total:        p.Total,
+	})
+}
+
+// WithPath Set the base path the page links are built from.
+func (p *SimplePaginator) WithPath(path string) *SimplePaginator {
+	p.Path = path
+	return p
+}
+
+// Url Get the link to the given page.
+func (p *SimplePaginator) Url(page uint64) string {
+	return pageUrl(p.Path, "page", strconv.FormatUint(page, 10))
+}
+
+// NextPageUrl Get the link to the next page, empty on the last page.
+func (p *SimplePaginator) NextPageUrl() string {
+	if !p.HasMore {
+		return ""
+	}
+	return p.Url(p.CurrentPage + 1)
+}
+
+// PreviousPageUrl Get the link to the previous page, empty on the first page.
+func (p *SimplePaginator) PreviousPageUrl() string {
+	if p.CurrentPage <= 1 {
+		return ""
+	}
+	return p.Url(p.CurrentPage - 1)
+}
+
+// MarshalJSON Encode the page with its pagination details.
+func (p *SimplePaginator) MarshalJSON() ([]byte, error) {
+	from, to := pageRange(p.CurrentPage, p.PerPage, p.Items)
+
+	return json.Marshal(struct {
+		CurrentPage  uint64      `json:"current_page"`
+		Data         interface{} `json:"data"`
+		FirstPageUrl string      `json:"first_page_url"`
+		From         *uint64     `json:"from"`
+		NextPageUrl  *string     `json:"next_page_url"`
+		Path         string      `json:"path"`
+		PerPage      uint64      `json:"per_page"`
+		PrevPageUrl  *string     `json:"prev_page_url"`
+		To           *uint64     `json:"to"`
+	}{
+		CurrentPage:  p.CurrentPage,
+		Data:         jsonItems(p.Items),
+		FirstPageUrl: p.Url(1),
+		From:         from,
+		NextPageUrl:  nullString(p.NextPageUrl()),
+		Path:         p.Path,
+		PerPage:      p.PerPage,
+		PrevPageUrl:  nullString(p.PreviousPageUrl()),
+		To:           to,
+	})
+}
+
+// WithPath Set the base path the page links are built from.
+func (p *CursorPaginator) WithPath(path string) *CursorPaginator {
+	p.Path = path
+	return p
+}
+
+// NextPageUrl Get the link to the next page, empty on the last page.
+func (p *CursorPaginator) NextPageUrl() string {
+	if p.NextCursor == "" {
+		return ""
+	}
+	return pageUrl(p.Path, "cursor", p.NextCursor)
+}
+
+// MarshalJSON Encode the page with its pagination details.
+func (p *CursorPaginator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Data        interface{} `json:"data"`
+		NextCursor  *string     `json:"next_cursor"`
+		NextPageUrl *string     `json:"next_page_url"`
+		Path        string      `json:"path"`
+		PerPage     uint64      `json:"per_page"`
+	}{
+		Data:        jsonItems(p.Items),
+		NextCursor:  nullString(p.NextCursor),
+		NextPageUrl: nullString(p.NextPageUrl()),
+		Path:        p.Path,
+		PerPage:     p.PerPage,
+	})
+}
+
+// cursorTime The form of a time value in a cursor token, tagged so that it
+// is decoded as a time.Time rather than compared as a string.
+type cursorTime struct {
+	Time string `json:"$time"`
+}
+
+// encodeCursor Encode the values of the ordered columns of a row into an
+// opaque cursor token.
+func encodeCursor(values map[string]interface{}) (string, error) {
+	tagged := make(map[string]interface{}, len(values))
+	for column, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			tagged[column] = cursorTime{v.Format(time.RFC3339Nano)}
+		case *time.Time:
+			tagged[column] = cursorTime{v.Format(time.RFC3339Nano)}
+		default:
+			tagged[column] = value
+		}
+	}
+
+	b, err := json.Marshal(tagged)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor Decode the column values held by a cursor token. Numbers are
+// kept as int64 where possible, so that large keys do not lose precision, and
+// times are restored as time.Time.
+func decodeCursor(cursor string) (map[string]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid pagination cursor")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, errors.New("invalid pagination cursor")
+	}
+
+	for column, value := range values {
+		switch v := value.(type) {
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				values[column] = i
+			} else if f, err := v.Float64(); err == nil {
+				values[column] = f
+			}
+		case map[string]interface{}:
+			s, _ := v["$time"].(string)
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, errors.New("invalid pagination cursor")
+			}
+			values[column] = t
+		}
+	}
+
+	return values, nil
+}
+
+// columnAlias Get the unqualified name of a column.
+func columnAlias(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
+
+// truncate Shorten the slice dest points to to the given length.
+func truncate(dest interface{}, length int) {
+	slice := reflect.ValueOf(dest).Elem()
+	slice.Set(slice.Slice(0, length))
+}
+
+// pageRange Get the numbers of the first and the last result on a page, nil
+// for an empty page.
+func pageRange(page uint64, perPage uint64, items interface{}) (*uint64, *uint64) {
+	count := uint64(reflect.Indirect(reflect.ValueOf(items)).Len())
+	if count == 0 {
+		return nil, nil
+	}
+
+	from := (page-1)*perPage + 1
+	to := from + count - 1
+
+	return &from, &to
+}
+
+// jsonItems Get the items of a page to encode, an empty slice encoding as []
+// rather than null.
+func jsonItems(items interface{}) interface{} {
+	if reflect.Indirect(reflect.ValueOf(items)).Len() == 0 {
+		return []interface{}{}
+	}
+	return items
+}
+
+// pageUrl Add a query string parameter to the path.
+func pageUrl(path string, name string, value string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + name + "=" + value
+}
+
+// nullString Get a pointer to the string, nil for an empty one, so that it
+// encodes as null.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
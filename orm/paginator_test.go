@@ -0,0 +1,161 @@
+package orm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPaginate(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid", "dan", "eve")
+
+	var users []testUser
+	paginator, err := conn.Table("users").OrderBy("id").Paginate(2, 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paginator.Total != 5 || paginator.LastPage != 3 {
+		t.Errorf("invalid paginator totals: got:%d/%d want:%d/%d", paginator.Total, paginator.LastPage, 5, 3)
+	}
+	if len(users) != 2 || users[0].Name != "cid" {
+		t.Errorf("invalid page: got:%v", users)
+	}
+
+	b, err := json.Marshal(paginator.WithPath("/users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var page map[string]interface{}
+	if err := json.Unmarshal(b, &page); err != nil {
+		t.Fatal(err)
+	}
+	if page["next_page_url"] != "/users?page=3" || page["prev_page_url"] != "/users?page=1" {
+		t.Errorf("invalid page links: got:%v %v", page["next_page_url"], page["prev_page_url"])
+	}
+	if page["from"] != float64(3) || page["to"] != float64(4) {
+		t.Errorf("invalid page range: got:%v-%v want:%d-%d", page["from"], page["to"], 3, 4)
+	}
+}
+
+func TestPaginateGrouped(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "ana", "bob", "cid")
+
+	var names []testUser
+	paginator, err := conn.Table("users").Select("name").GroupBy("name").OrderBy("name").Paginate(1, 10, &names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paginator.Total != 3 || len(names) != 3 {
+		t.Errorf("invalid grouped page: got:%d/%d want:%d/%d", paginator.Total, len(names), 3, 3)
+	}
+}
+
+func TestSimplePaginate(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "cid")
+
+	var users []testUser
+	paginator, err := conn.Table("users").OrderBy("id").SimplePaginate(1, 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || !paginator.HasMore {
+		t.Errorf("invalid first page: got:%d rows, more:%v", len(users), paginator.HasMore)
+	}
+
+	paginator, err = conn.Table("users").OrderBy("id").SimplePaginate(2, 2, &users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || paginator.HasMore {
+		t.Errorf("invalid last page: got:%d rows, more:%v", len(users), paginator.HasMore)
+	}
+}
+
+func TestCursorPaginate(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana", "bob", "bob", "cid", "dan")
+
+	var names []string
+	var cursor string
+	for pages := 0; pages < 5; pages++ {
+		var users []testUser
+		paginator, err := conn.Table("users").
+			Where("name", "<>", "dan").
+			OrderByDesc("name").
+			OrderBy("id").
+			CursorPaginate(2, cursor, &users)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, user := range users {
+			names = append(names, user.Name)
+		}
+		if cursor = paginator.NextCursor; cursor == "" {
+			break
+		}
+	}
+
+	want := []string{"cid", "bob", "bob", "ana"}
+	if len(names) != len(want) {
+		t.Fatalf("invalid cursor pages: got:%v want:%v", names, want)
+	}
+	for i := range names {
+		if names[i] != want[i] {
+			t.Errorf("invalid cursor row %d: got:%s want:%s", i, names[i], want[i])
+		}
+	}
+}
+
+func TestPaginateZeroPerPage(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana")
+
+	var users []testUser
+	if _, err := conn.Table("users").OrderBy("id").Paginate(1, 0, &users); err == nil {
+		t.Errorf("invalid paginate error: got:nil want:error")
+	}
+	if _, err := conn.Table("users").OrderBy("id").SimplePaginate(1, 0, &users); err == nil {
+		t.Errorf("invalid simple paginate error: got:nil want:error")
+	}
+	if _, err := conn.Table("users").OrderBy("id").CursorPaginate(0, "", &users); err == nil {
+		t.Errorf("invalid cursor paginate error: got:nil want:error")
+	}
+}
+
+func TestCursorPaginateTimestamps(t *testing.T) {
+	conn := newArticleConnection(t)
+
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		_, _, err := conn.Table("articles").Insert(map[string]interface{}{
+			"title":      "article",
+			"created_at": created.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var pages []int
+	var cursor string
+	for len(pages) < 4 {
+		var articles []testArticle
+		paginator, err := conn.Table("articles").OrderBy("created_at").CursorPaginate(2, cursor, &articles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, len(articles))
+		if cursor = paginator.NextCursor; cursor == "" {
+			break
+		}
+	}
+
+	if len(pages) != 2 || pages[0] != 2 || pages[1] != 2 {
+		t.Errorf("invalid timestamp cursor pages: got:%v want:%v", pages, []int{2, 2})
+	}
+}
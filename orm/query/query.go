@@ -2,11 +2,12 @@ package query
 
 type Query struct {
 	Distinct    bool
-	Columns     []string
+	Columns     []interface{}
 	From        string
+	FromSub     *Sub
 	Joins       []*Join
 	Wheres      []*Where
-	Groups      []string
+	Groups      []interface{}
 	Havings     []*Having
 	Orders      []*Order
 	Unions      []*Union
@@ -16,9 +17,28 @@ type Query struct {
 	UnionLimit  uint64
 	UnionOffset uint64
 	Aggregate   *Aggregate
+	Lock        *Lock
 	JoinClause  bool
 }
 
+// The options of a pessimistic lock.
+const (
+	NoWait     = "NOWAIT"
+	SkipLocked = "SKIP LOCKED"
+)
+
+type Lock struct {
+	Shared bool
+	Option string
+}
+
+// Sub A subquery aliased within its parent query, as a column or as the
+// table the parent query selects from.
+type Sub struct {
+	Query *Query
+	As    string
+}
+
 type Aggregate struct {
 	Function string
 	Columns  []string
@@ -41,6 +61,7 @@ type Where struct {
 	Values   []interface{}
 	Boolean  string
 	Not      bool
+	Query    *Query
 }
 
 type Having struct {
@@ -60,6 +81,8 @@ type Order struct {
 }
 
 type Union struct {
+	Query *Query
+	All   bool
 }
 
 type UnionOrder struct {
@@ -68,3 +91,85 @@ type UnionOrder struct {
 	Column    string
 	Direction string
 }
+
+// Clone Get a deep copy of the query, so that changing either one, or any
+// of their subqueries, leaves the other untouched.
+func (q *Query) Clone() *Query {
+	if q == nil {
+		return nil
+	}
+
+	c := *q
+
+	c.Columns = nil
+	for _, column := range q.Columns {
+		if sub, ok := column.(*Sub); ok {
+			column = sub.Clone()
+		}
+		c.Columns = append(c.Columns, column)
+	}
+
+	c.FromSub = q.FromSub.Clone()
+	c.Groups = append([]interface{}(nil), q.Groups...)
+
+	c.Joins = nil
+	for _, join := range q.Joins {
+		j := *join
+		j.Query = join.Query.Clone()
+		c.Joins = append(c.Joins, &j)
+	}
+
+	c.Wheres = nil
+	for _, where := range q.Wheres {
+		w := *where
+		w.Values = append([]interface{}(nil), where.Values...)
+		w.Query = where.Query.Clone()
+		c.Wheres = append(c.Wheres, &w)
+	}
+
+	c.Havings = nil
+	for _, having := range q.Havings {
+		h := *having
+		c.Havings = append(c.Havings, &h)
+	}
+
+	c.Orders = nil
+	for _, order := range q.Orders {
+		o := *order
+		c.Orders = append(c.Orders, &o)
+	}
+
+	c.Unions = nil
+	for _, union := range q.Unions {
+		u := *union
+		u.Query = union.Query.Clone()
+		c.Unions = append(c.Unions, &u)
+	}
+
+	c.UnionOrders = nil
+	for _, order := range q.UnionOrders {
+		o := *order
+		c.UnionOrders = append(c.UnionOrders, &o)
+	}
+
+	if q.Aggregate != nil {
+		a := *q.Aggregate
+		a.Columns = append([]string(nil), q.Aggregate.Columns...)
+		c.Aggregate = &a
+	}
+
+	if q.Lock != nil {
+		l := *q.Lock
+		c.Lock = &l
+	}
+
+	return &c
+}
+
+// Clone Get a deep copy of the subquery.
+func (s *Sub) Clone() *Sub {
+	if s == nil {
+		return nil
+	}
+	return &Sub{Query: s.Query.Clone(), As: s.As}
+}
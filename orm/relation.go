@@ -0,0 +1,327 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/glugox/unogo/support"
+)
+
+// The relation types a model field can declare in its torm tag.
+const (
+	HasOne        = "HAS_ONE"
+	HasMany       = "HAS_MANY"
+	BelongsTo     = "BELONGS_TO"
+	BelongsToMany = "BELONGS_TO_MANY"
+)
+
+// Relation The relation between a model and the models held by one of its
+// fields. Relations are declared in the torm tag of that field:
+//
+//	Profile *Profile `torm:"has_one"`
+//	Posts   []Post   `torm:"has_many;foreign_key:author_id;local_key:id"`
+//	Author  *User    `torm:"belongs_to;foreign_key:author_id;owner_key:id"`
+//	Tags    []Tag    `torm:"belongs_to_many;pivot:post_tags;foreign_pivot_key:post_id;related_pivot_key:tag_id"`
+//
+// Keys that are left out follow the usual naming conventions, e.g. the
+// foreign key of a User relation is user_id and the pivot table of Post and
+// Tag is post_tag.
+type Relation struct {
+	Type    string
+	Name    string
+	Related reflect.Type
+	Table   string
+
+	// ParentKey The column of the parent model the related models are matched by.
+	ParentKey string
+
+	// RelatedKey The column of the related model matched against ParentKey,
+	// or against RelatedPivotKey for a BelongsToMany relation.
+	RelatedKey string
+
+	Pivot           string
+	ForeignPivotKey string
+	RelatedPivotKey string
+}
+
+// pivotRecord A row of a pivot table, selected with aliased key columns.
+type pivotRecord struct {
+	Parent  interface{} `torm:"column:pivot_parent"`
+	Related interface{} `torm:"column:pivot_related"`
+}
+
+// NewRelation Get the relation declared by a field of the parent schema.
+func NewRelation(parentType reflect.Type, parent *Schema, field *Field) (*Relation, error) {
+	related := field.StructField.Type
+	if related.Kind() == reflect.Slice {
+		related = related.Elem()
+	}
+	if related.Kind() == reflect.Ptr {
+		related = related.Elem()
+	}
+	if related.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("relation [%s] should hold a struct or a slice of structs", field.Name)
+	}
+
+	relatedSchema, err := NewSchema(reflect.New(related).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	relation := &Relation{
+		Type:    field.Relation,
+		Name:    field.Name,
+		Related: related,
+		Table:   tableName(related),
+	}
+
+	attr := func(key string, value string) string {
+		if v, ok := field.GetAttr(key); ok && v != "" {
+			return v
+		}
+		return value
+	}
+
+	parentName := support.SnakeCase(parentType.Name())
+	relatedName := support.SnakeCase(related.Name())
+
+	switch field.Relation {
+	case HasOne, HasMany:
+		relation.ParentKey = attr("local_key", parent.KeyName())
+		relation.RelatedKey = attr("foreign_key", parentName+"_id")
+	case BelongsTo:
+		relation.ParentKey = attr("foreign_key", support.SnakeCase(field.StructField.Name)+"_id")
+		relation.RelatedKey = attr("owner_key", relatedSchema.KeyName())
+	case BelongsToMany:
+		names := []string{parentName, relatedName}
+		sort.Strings(names)
+
+		relation.ParentKey = attr("parent_key", parent.KeyName())
+		relation.RelatedKey = attr("related_key", relatedSchema.KeyName())
+		relation.Pivot = attr("pivot", strings.Join(names, "_"))
+		relation.ForeignPivotKey = attr("foreign_pivot_key", parentName+"_id")
+		relation.RelatedPivotKey = attr("related_pivot_key", relatedName+"_id")
+	}
+
+	return relation, nil
+}
+
+// With Set the relationships that should be eager loaded. Nested relations
+// are separated by dots, e.g. With("posts", "posts.comments").
+func (b *Builder) With(relations ...string) *Builder {
+	b.eagerLoad = append(b.eagerLoad, relations...)
+	return b
+}
+
+// eagerLoadRelations Eager load the relations set with With onto the models
+// in dest. Every relation costs one query for all of the models, plus one for
+// the pivot table of a BelongsToMany relation.
+func (b *Builder) eagerLoadRelations(dest interface{}) error {
+	models := collectModels(reflect.Indirect(reflect.ValueOf(dest)))
+	if len(models) == 0 {
+		return nil
+	}
+
+	var names []string
+	nested := make(map[string][]string)
+
+	for _, relation := range b.eagerLoad {
+		segments := strings.SplitN(relation, ".", 2)
+		name := segments[0]
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if len(segments) > 1 {
+			nested[name] = append(nested[name], segments[1])
+		}
+	}
+
+	for _, name := range names {
+		if err := b.eagerLoadRelation(models, name, nested[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eagerLoadRelation Load a single relation onto the models.
+func (b *Builder) eagerLoadRelation(models []reflect.Value, name string, nested []string) error {
+	schemas := make([]*Schema, len(models))
+	for i, model := range models {
+		schema, err := NewSchema(model.Addr().Interface())
+		if err != nil {
+			return err
+		}
+		schemas[i] = schema
+	}
+
+	field, ok := schemas[0].Relation(name)
+	if !ok {
+		return fmt.Errorf("relation [%s] not defined on model [%s]", name, models[0].Type())
+	}
+
+	relation, err := NewRelation(models[0].Type(), schemas[0], field)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]interface{}, 0, len(schemas))
+	seen := make(map[string]bool)
+	for _, schema := range schemas {
+		if key, ok := schema.Field(relation.ParentKey); ok && !isNilValue(key.Value) {
+			k := relationKey(key.Value.Interface())
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, key.Value.Interface())
+			}
+		}
+	}
+
+	// Map each parent key to the keys of its related models. Only the
+	// BelongsToMany relation goes through a pivot table, every other
+	// relation matches the related models by their own key column.
+	var pivot map[string][]string
+	relatedKeys := keys
+
+	if relation.Type == BelongsToMany && len(keys) > 0 {
+		var records []pivotRecord
+		err := b.Connection.Table(relation.Pivot).
+			Select(
+				relation.ForeignPivotKey+" as pivot_parent",
+				relation.RelatedPivotKey+" as pivot_related",
+			).
+			WhereIn(relation.ForeignPivotKey, keys).
+			Get(&records)
+		if err != nil {
+			return err
+		}
+
+		pivot = make(map[string][]string)
+		relatedKeys = nil
+		seen = make(map[string]bool)
+		for _, record := range records {
+			parent, related := relationKey(record.Parent), relationKey(record.Related)
+			pivot[parent] = append(pivot[parent], related)
+			if !seen[related] {
+				seen[related] = true
+				relatedKeys = append(relatedKeys, record.Related)
+			}
+		}
+	}
+
+	dictionary := make(map[string][]reflect.Value)
+
+	if len(relatedKeys) > 0 {
+		results := reflect.New(reflect.SliceOf(relation.Related))
+		err := b.Connection.Model(reflect.New(relation.Related).Interface()).
+			WhereIn(relation.RelatedKey, relatedKeys).
+			With(nested...).
+			Get(results.Interface())
+		if err != nil {
+			return err
+		}
+
+		results = results.Elem()
+		for i := 0; i < results.Len(); i++ {
+			result := results.Index(i)
+			schema, err := NewSchema(result.Addr().Interface())
+			if err != nil {
+				return err
+			}
+			if key, ok := schema.Field(relation.RelatedKey); ok {
+				k := relationKey(key.Value.Interface())
+				dictionary[k] = append(dictionary[k], result)
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		key, ok := schema.Field(relation.ParentKey)
+		if !ok || isNilValue(key.Value) {
+			continue
+		}
+
+		var matches []reflect.Value
+		if pivot != nil {
+			for _, related := range pivot[relationKey(key.Value.Interface())] {
+				matches = append(matches, dictionary[related]...)
+			}
+		} else {
+			matches = dictionary[relationKey(key.Value.Interface())]
+		}
+
+		field, _ := schema.Relation(name)
+		setRelation(field.Value, matches)
+	}
+
+	return nil
+}
+
+// collectModels Get the addressable struct values held by a query destination.
+func collectModels(value reflect.Value) []reflect.Value {
+	var models []reflect.Value
+
+	switch value.Kind() {
+	case reflect.Struct:
+		models = append(models, value)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			if item.Kind() == reflect.Ptr {
+				if item.IsNil() {
+					continue
+				}
+				item = item.Elem()
+			}
+			models = append(models, item)
+		}
+	}
+
+	return models
+}
+
+// setRelation Set the matched related models on a relation field.
+func setRelation(field reflect.Value, matches []reflect.Value) {
+	switch field.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), 0, len(matches))
+		for _, match := range matches {
+			if field.Type().Elem().Kind() == reflect.Ptr {
+				match = match.Addr()
+			}
+			slice = reflect.Append(slice, match)
+		}
+		field.Set(slice)
+	case reflect.Ptr:
+		if len(matches) > 0 {
+			field.Set(matches[0].Addr())
+		} else {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	case reflect.Struct:
+		if len(matches) > 0 {
+			field.Set(matches[0])
+		}
+	}
+}
+
+// relationKey Get the dictionary key for a key column value, so that values
+// read into different Go types (int64, uint, []byte...) still match.
+func relationKey(value interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return ""
+	}
+	if b, ok := v.Interface().([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func isNilValue(value reflect.Value) bool {
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
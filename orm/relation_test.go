@@ -0,0 +1,152 @@
+package orm
+
+import (
+	"testing"
+)
+
+type testAuthor struct {
+	ID      int64 `torm:"primary_key"`
+	Name    string
+	Profile *testProfile `torm:"has_one;foreign_key:author_id"`
+	Posts   []testPost   `torm:"has_many;foreign_key:author_id"`
+	Drafts  []*testPost  `torm:"has_many;foreign_key:author_id"`
+}
+
+func (a *testAuthor) TableName() string {
+	return "authors"
+}
+
+type testProfile struct {
+	ID       int64 `torm:"primary_key"`
+	AuthorID int64
+	Bio      string
+}
+
+func (p *testProfile) TableName() string {
+	return "profiles"
+}
+
+type testPost struct {
+	ID       int64 `torm:"primary_key"`
+	AuthorID int64
+	Title    string
+	Author   *testAuthor   `torm:"belongs_to;foreign_key:author_id"`
+	Comments []testComment `torm:"has_many;foreign_key:post_id"`
+	Tags     []testTag     `torm:"belongs_to_many;pivot:post_tag;foreign_pivot_key:post_id;related_pivot_key:tag_id"`
+}
+
+func (p *testPost) TableName() string {
+	return "posts"
+}
+
+type testComment struct {
+	ID     int64 `torm:"primary_key"`
+	PostID int64
+	Body   string
+}
+
+func (c *testComment) TableName() string {
+	return "comments"
+}
+
+type testTag struct {
+	ID   int64 `torm:"primary_key"`
+	Name string
+}
+
+func (t *testTag) TableName() string {
+	return "tags"
+}
+
+func newRelationConnection(t *testing.T) *Connection {
+	t.Helper()
+
+	conn := newTestConnection(t)
+	statements := []string{
+		"CREATE TABLE authors (id INTEGER PRIMARY KEY, name VARCHAR(255))",
+		"CREATE TABLE profiles (id INTEGER PRIMARY KEY, author_id INTEGER, bio VARCHAR(255))",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, author_id INTEGER, title VARCHAR(255))",
+		"CREATE TABLE comments (id INTEGER PRIMARY KEY, post_id INTEGER, body VARCHAR(255))",
+		"CREATE TABLE tags (id INTEGER PRIMARY KEY, name VARCHAR(255))",
+		"CREATE TABLE post_tag (post_id INTEGER, tag_id INTEGER)",
+		"INSERT INTO authors (id, name) VALUES (1, 'ana'), (2, 'bob')",
+		"INSERT INTO profiles (id, author_id, bio) VALUES (1, 2, 'bob bio')",
+		"INSERT INTO posts (id, author_id, title) VALUES (1, 1, 'first'), (2, 1, 'second'), (3, 2, 'third')",
+		"INSERT INTO comments (id, post_id, body) VALUES (1, 1, 'a'), (2, 1, 'b'), (3, 3, 'c')",
+		"INSERT INTO tags (id, name) VALUES (1, 'go'), (2, 'sql')",
+		"INSERT INTO post_tag (post_id, tag_id) VALUES (1, 1), (1, 2), (3, 2)",
+	}
+	for _, statement := range statements {
+		if err := conn.Statement(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return conn
+}
+
+func TestEagerLoadHasManyAndHasOne(t *testing.T) {
+	conn := newRelationConnection(t)
+
+	var authors []testAuthor
+	err := conn.Table("authors").With("posts", "profile", "drafts", "posts.comments").OrderBy("id").Get(&authors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(authors) != 2 {
+		t.Fatalf("invalid author count: got:%d want:%d", len(authors), 2)
+	}
+	if len(authors[0].Posts) != 2 || len(authors[1].Posts) != 1 {
+		t.Errorf("invalid posts: got:%d,%d want:2,1", len(authors[0].Posts), len(authors[1].Posts))
+	}
+	if len(authors[0].Drafts) != 2 || authors[0].Drafts[1].Title != "second" {
+		t.Errorf("invalid drafts: got:%+v", authors[0].Drafts)
+	}
+	if authors[0].Profile != nil {
+		t.Errorf("expecting no profile, got:%+v", authors[0].Profile)
+	}
+	if authors[1].Profile == nil || authors[1].Profile.Bio != "bob bio" {
+		t.Errorf("invalid profile: got:%+v", authors[1].Profile)
+	}
+
+	for _, post := range authors[0].Posts {
+		want := map[int64]int{1: 2, 2: 0}[post.ID]
+		if len(post.Comments) != want {
+			t.Errorf("invalid comment count for post %d: got:%d want:%d", post.ID, len(post.Comments), want)
+		}
+	}
+}
+
+func TestEagerLoadBelongsToAndBelongsToMany(t *testing.T) {
+	conn := newRelationConnection(t)
+
+	var posts []*testPost
+	err := conn.Table("posts").With("author", "tags").OrderBy("id").Get(&posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantAuthors := []string{"ana", "ana", "bob"}
+	wantTags := []int{2, 0, 1}
+	for i, post := range posts {
+		if post.Author == nil || post.Author.Name != wantAuthors[i] {
+			t.Errorf("invalid author for post %d: got:%+v", post.ID, post.Author)
+		}
+		if len(post.Tags) != wantTags[i] {
+			t.Errorf("invalid tag count for post %d: got:%d want:%d", post.ID, len(post.Tags), wantTags[i])
+		}
+	}
+	if posts[2].Tags[0].Name != "sql" {
+		t.Errorf("invalid tag: got:%s want:%s", posts[2].Tags[0].Name, "sql")
+	}
+}
+
+func TestEagerLoadUndefinedRelation(t *testing.T) {
+	conn := newRelationConnection(t)
+
+	var post testPost
+	if err := conn.Table("posts").With("editor").First(&post); err == nil {
+		t.Error("expecting an error for an undefined relation")
+	}
+}
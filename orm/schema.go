@@ -36,6 +36,28 @@ func (s *Schema) Attributes() map[string]interface{} {
 	return s.attributes
 }
 
+// InsertAttributes Get the attributes for an insert statement. A blank primary
+// key is left out so that the database can generate it.
+func (s *Schema) InsertAttributes() map[string]interface{} {
+	attributes := make(map[string]interface{})
+	for name, value := range s.Attributes() {
+		if s.PrimaryField != nil && s.PrimaryField.IsBlank && s.PrimaryField.Name == name {
+			continue
+		}
+		attributes[name] = value
+	}
+
+	return attributes
+}
+
+// KeyName Get the primary key column of the model.
+func (s *Schema) KeyName() string {
+	if s.PrimaryField != nil {
+		return s.PrimaryField.Name
+	}
+	return "id"
+}
+
 func NewSchema(model interface{}) (*Schema, error) {
 	results := reflect.Indirect(reflect.ValueOf(model))
 
@@ -44,16 +66,9 @@ func NewSchema(model interface{}) (*Schema, error) {
 		return nil, errors.New("unsupported value, should be struct")
 	}
 
-	var resultType reflect.Type
-	var resultValue reflect.Value
-
-	resultType = results.Type()
-	resultValue = results
-
 	var schema Schema
 
-	for i := 0; i < resultType.NumField(); i++ {
-		field := NewField(resultValue.Field(i), resultType.Field(i))
+	for _, field := range structFields(results) {
 		if schema.PrimaryField == nil && field.Primary {
 			schema.PrimaryField = field
 		}
@@ -62,3 +77,59 @@ func NewSchema(model interface{}) (*Schema, error) {
 
 	return &schema, nil
 }
+
+// Relation Get the relation field with the given name.
+func (s *Schema) Relation(name string) (*Field, bool) {
+	for _, field := range s.Fields {
+		if field.Relation != "" && field.Name == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// Field Get the column field with the given name.
+func (s *Schema) Field(name string) (*Field, bool) {
+	for _, field := range s.Fields {
+		if !field.Ignored && field.Name == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// TimestampField Get the field tagged with one of the CreatedAt, UpdatedAt
+// or DeletedAt timestamp tags.
+func (s *Schema) TimestampField(tag string) (*Field, bool) {
+	for _, field := range s.Fields {
+		if _, ok := field.GetAttr(tag); ok && !field.Ignored {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// structFields Get the fields of a struct value. The fields of embedded
+// structs such as Model and SoftDeletes are promoted to the outer struct.
+func structFields(value reflect.Value) []*Field {
+	var fields []*Field
+
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct && structField.Tag.Get("torm") == "" {
+			fields = append(fields, structFields(value.Field(i))...)
+			continue
+		}
+
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		fields = append(fields, NewField(value.Field(i), structField))
+	}
+
+	return fields
+}
@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"strings"
+	"time"
+
+	"github.com/glugox/unogo/orm/query"
+)
+
+const (
+	withTrashed = "with"
+	onlyTrashed = "only"
+)
+
+// WithTrashed Include soft deleted models in the results.
+func (b *Builder) WithTrashed() *Builder {
+	b.trashed = withTrashed
+	return b
+}
+
+// OnlyTrashed Only get the soft deleted models.
+func (b *Builder) OnlyTrashed() *Builder {
+	b.trashed = onlyTrashed
+	return b
+}
+
+// Restore Restore the soft deleted models matched by the query.
+func (b *Builder) Restore() (int64, error) {
+	if b.softDelete == "" {
+		return 0, nil
+	}
+
+	if b.trashed == "" {
+		b.WithTrashed()
+	}
+
+	return b.Update(map[string]interface{}{b.softDelete: nil})
+}
+
+// ForceDelete Permanently delete the records, even for models using soft deletes.
+func (b *Builder) ForceDelete(args ...interface{}) (int64, error) {
+	softDelete := b.softDelete
+	b.softDelete = ""
+	defer func() { b.softDelete = softDelete }()
+
+	return b.Delete(args...)
+}
+
+// scopedQuery Get the query with the soft delete constraint applied. Models
+// using soft deletes exclude deleted rows, unless WithTrashed was called.
+// The builder's own query is left untouched.
+func (b *Builder) scopedQuery() *query.Query {
+	if b.softDelete == "" || b.trashed == withTrashed {
+		return b.Query
+	}
+
+	scoped := *b.Query
+	scoped.Wheres = groupWheres(b.Query.Wheres)
+
+	whereType := "Null"
+	if b.trashed == onlyTrashed {
+		whereType = "NotNull"
+	}
+
+	scoped.Wheres = append(scoped.Wheres, &query.Where{
+		Type:    whereType,
+		Column:  tableAlias(b.Query.From) + "." + b.softDelete,
+		Boolean: "and",
+	})
+
+	return &scoped
+}
+
+// softDeleteValues Get the values a delete statement updates for models
+// using soft deletes.
+func (b *Builder) softDeleteValues() map[string]interface{} {
+	return map[string]interface{}{b.softDelete: time.Now()}
+}
+
+// tableAlias Get the name a table is referenced by in the query, its alias
+// for "users as u".
+func tableAlias(table string) string {
+	if i := strings.Index(strings.ToLower(table), " as "); i >= 0 {
+		return strings.TrimSpace(table[i+4:])
+	}
+	return table
+}
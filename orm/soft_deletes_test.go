@@ -0,0 +1,153 @@
+package orm
+
+import (
+	"testing"
+)
+
+type testArticle struct {
+	Model
+	SoftDeletes
+	Title string
+}
+
+func (a *testArticle) TableName() string {
+	return "articles"
+}
+
+func newArticleConnection(t *testing.T) *Connection {
+	t.Helper()
+
+	conn := newTestConnection(t)
+	err := conn.Statement(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title VARCHAR(255),
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestCreateAndSaveTouchTimestamps(t *testing.T) {
+	conn := newArticleConnection(t)
+
+	article := &testArticle{Title: "draft"}
+	if err := conn.Create(article); err != nil {
+		t.Fatal(err)
+	}
+	if article.ID == 0 || article.CreatedAt.IsZero() || article.UpdatedAt.IsZero() {
+		t.Fatalf("expecting id and timestamps to be set, got:%+v", article)
+	}
+
+	created := article.CreatedAt
+	article.Title = "published"
+	if err := conn.Save(article); err != nil {
+		t.Fatal(err)
+	}
+	if !article.CreatedAt.Equal(created) || article.UpdatedAt.Before(created) {
+		t.Errorf("invalid timestamps after save: got:%+v", article)
+	}
+
+	var found testArticle
+	if err := conn.Model(&found).Where("id", article.ID).First(&found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Title != "published" || found.CreatedAt.IsZero() || found.DeletedAt != nil {
+		t.Errorf("invalid stored article: got:%+v", found)
+	}
+}
+
+func TestDestroySoftDeletes(t *testing.T) {
+	conn := newArticleConnection(t)
+
+	first, second := &testArticle{Title: "first"}, &testArticle{Title: "second"}
+	for _, article := range []*testArticle{first, second} {
+		if err := conn.Create(article); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := conn.Destroy(first); err != nil {
+		t.Fatal(err)
+	}
+	if first.DeletedAt == nil {
+		t.Error("expecting deleted_at to be set on the model")
+	}
+
+	count := func(b *Builder) int {
+		var n int
+		if err := b.Count(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if n := count(conn.Model(&testArticle{})); n != 1 {
+		t.Errorf("invalid count: got:%d want:%d", n, 1)
+	}
+	if n := count(conn.Model(&testArticle{}).WithTrashed()); n != 2 {
+		t.Errorf("invalid count with trashed: got:%d want:%d", n, 2)
+	}
+	if n := count(conn.Model(&testArticle{}).OnlyTrashed()); n != 1 {
+		t.Errorf("invalid count of trashed: got:%d want:%d", n, 1)
+	}
+	if n := count(conn.Model(&testArticle{}).Where("id", first.ID).OrWhere("id", second.ID)); n != 1 {
+		t.Errorf("invalid count with or where: got:%d want:%d", n, 1)
+	}
+
+	if _, err := conn.Model(&testArticle{}).Where("id", first.ID).Restore(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(conn.Model(&testArticle{})); n != 2 {
+		t.Errorf("invalid count after restore: got:%d want:%d", n, 2)
+	}
+
+	if _, err := conn.Model(&testArticle{}).Where("id", second.ID).ForceDelete(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(conn.Model(&testArticle{}).WithTrashed()); n != 1 {
+		t.Errorf("invalid count after force delete: got:%d want:%d", n, 1)
+	}
+}
+
+func TestSaveSoftDeleted(t *testing.T) {
+	conn := newArticleConnection(t)
+
+	article := &testArticle{Title: "draft"}
+	if err := conn.Create(article); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Destroy(article); err != nil {
+		t.Fatal(err)
+	}
+
+	article.Title = "trashed"
+	if err := conn.Save(article); err != nil {
+		t.Fatal(err)
+	}
+
+	var found testArticle
+	if err := conn.Model(&found).WithTrashed().Where("id", article.ID).First(&found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Title != "trashed" || found.DeletedAt == nil {
+		t.Errorf("invalid saved trashed article: got:%+v", found)
+	}
+
+	article.DeletedAt = nil
+	if err := conn.Save(article); err != nil {
+		t.Fatal(err)
+	}
+
+	found = testArticle{}
+	if err := conn.Model(&found).Where("id", article.ID).First(&found); err != nil {
+		t.Fatal(err)
+	}
+	if found.Title != "trashed" || found.DeletedAt != nil {
+		t.Errorf("invalid restored article: got:%+v", found)
+	}
+}
@@ -2,6 +2,7 @@ package orm
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/glugox/unogo/support"
 )
@@ -23,26 +24,93 @@ func (c *Connection) Model(model interface{}) *Builder {
 	if t, ok := model.(TableName); ok {
 		table = t.TableName()
 	} else {
-		modelType := reflect.ValueOf(model).Type()
-		if t, ok := reflect.New(modelType).Interface().(TableName); ok {
-			table = t.TableName()
-		} else {
-			table = support.SnakeCase(modelType.Name())
+		table = tableName(reflect.Indirect(reflect.ValueOf(model)).Type())
+	}
+
+	builder := c.Table(table)
+
+	if schema, err := NewSchema(model); err == nil {
+		if field, ok := schema.TimestampField(DeletedAt); ok {
+			builder.softDelete = field.Name
 		}
 	}
 
-	return c.Table(table)
+	return builder
+}
+
+// tableName Get the table of a model type, from its TableName method or
+// the snake cased type name.
+func tableName(modelType reflect.Type) string {
+	if t, ok := reflect.New(modelType).Interface().(TableName); ok {
+		return t.TableName()
+	}
+	return support.SnakeCase(modelType.Name())
 }
 
 // Create Save a new model to the database.
 func (c *Connection) Create(model interface{}) error {
+	hooks := []string{beforeSave, beforeCreate, afterCreate, afterSave}
+
+	return c.withHooks(model, hooks, func(conn *Connection) error {
+		if err := callHooks(model, conn, beforeSave, beforeCreate); err != nil {
+			return err
+		}
+		if err := conn.insertModel(model); err != nil {
+			return err
+		}
+		return callHooks(model, conn, afterCreate, afterSave)
+	})
+}
+
+// Save Save the model to the database.
+func (c *Connection) Save(model interface{}) error {
+	schema, err := NewSchema(model)
+	if err != nil {
+		return err
+	}
+
+	if schema.PrimaryField == nil || schema.PrimaryField.IsBlank {
+		return c.Create(model)
+	}
+
+	hooks := []string{beforeSave, beforeUpdate, afterUpdate, afterSave}
+
+	return c.withHooks(model, hooks, func(conn *Connection) error {
+		if err := callHooks(model, conn, beforeSave, beforeUpdate); err != nil {
+			return err
+		}
+		if err := conn.updateModel(model); err != nil {
+			return err
+		}
+		return callHooks(model, conn, afterUpdate, afterSave)
+	})
+}
+
+// Destroy Destroy the model. Models embedding SoftDeletes only get their
+// deleted_at column set.
+func (c *Connection) Destroy(model interface{}) error {
+	hooks := []string{beforeDelete, afterDelete}
+
+	return c.withHooks(model, hooks, func(conn *Connection) error {
+		if err := callHooks(model, conn, beforeDelete); err != nil {
+			return err
+		}
+		if err := conn.deleteModel(model); err != nil {
+			return err
+		}
+		return callHooks(model, conn, afterDelete)
+	})
+}
+
+func (c *Connection) insertModel(model interface{}) error {
 	schema, err := NewSchema(model)
 	if err != nil {
 		return err
 	}
 
-	attributes := schema.Attributes()
-	insertId, _, err := c.Model(model).Insert(attributes)
+	touchTimestamps(schema, true)
+
+	insertId, err := c.Model(model).InsertGetId(schema.InsertAttributes(), schema.KeyName())
 
 	if err != nil {
 		return err
@@ -53,32 +121,22 @@ func (c *Connection) Create(model interface{}) error {
 	return nil
 }
 
-// Save Save the model to the database.
-func (c *Connection) Save(model interface{}) error {
-	var err error
-	var schema *Schema
-	schema, err = NewSchema(model)
+func (c *Connection) updateModel(model interface{}) error {
+	schema, err := NewSchema(model)
 	if err != nil {
 		return err
 	}
 
-	attributes := schema.Attributes()
+	touchTimestamps(schema, false)
 
-	if schema.PrimaryField != nil && !schema.PrimaryField.IsBlank {
-		_, err = c.Model(model).Where(schema.PrimaryField.Name, schema.PrimaryField.Value.Addr().Interface()).Update(attributes)
-	} else {
-		var insertId int64
-		insertId, _, err = c.Model(model).Insert(attributes)
-		if err == nil {
-			schema.SetId(insertId)
-		}
-	}
+	// A soft deleted model is saved too, which also restores it when its
+	// deleted_at is cleared.
+	_, err = c.Model(model).WithTrashed().Where(schema.PrimaryField.Name, schema.PrimaryField.Value.Addr().Interface()).Update(schema.Attributes())
 
 	return err
 }
 
-// Destroy Destroy the model.
-func (c *Connection) Destroy(model interface{}) error {
+func (c *Connection) deleteModel(model interface{}) error {
 	var err error
 	var schema *Schema
 	schema, err = NewSchema(model)
@@ -86,11 +144,35 @@ func (c *Connection) Destroy(model interface{}) error {
 		return err
 	}
 
+	builder := c.Model(model)
+
 	if schema.PrimaryField != nil && !schema.PrimaryField.IsBlank {
-		_, err = c.Model(model).Where(schema.PrimaryField.Name, schema.PrimaryField.Value.Addr().Interface()).Delete()
-	} else {
-		_, err = c.Model(model).Delete()
+		builder.Where(schema.PrimaryField.Name, schema.PrimaryField.Value.Addr().Interface())
+	}
+
+	if field, ok := schema.TimestampField(DeletedAt); ok {
+		if err = field.SetValue(time.Now()); err != nil {
+			return err
+		}
+		_, err = builder.Update(map[string]interface{}{field.Name: field.Value.Interface()})
+		return err
 	}
 
+	_, err = builder.Delete()
+
 	return err
 }
+
+// touchTimestamps Set the created_at and updated_at fields of the model to
+// the current time. created_at is only set for new models that don't have it.
+func touchTimestamps(schema *Schema, creating bool) {
+	now := time.Now()
+
+	if field, ok := schema.TimestampField(CreatedAt); ok && creating && field.IsBlank {
+		field.SetValue(now)
+	}
+
+	if field, ok := schema.TimestampField(UpdatedAt); ok {
+		field.SetValue(now)
+	}
+}
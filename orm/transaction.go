@@ -0,0 +1,95 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoTransaction is returned when committing or rolling back a connection
+// that is not bound to a transaction.
+var ErrNoTransaction = errors.New("no active transaction")
+
+// Transaction Execute a closure within a transaction. The transaction is
+// rolled back when the closure returns an error or panics, and committed
+// otherwise. Calling Transaction on a connection that is already inside a
+// transaction uses a savepoint.
+func (c *Connection) Transaction(callback func(tx *Connection) error) (err error) {
+	tx, err := c.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = callback(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Begin Start a new database transaction and get a connection bound to it.
+// Beginning on a connection that is already bound to a transaction creates a
+// savepoint instead.
+func (c *Connection) Begin() (*Connection, error) {
+	tx := *c
+	tx.transactions++
+
+	if c.tx == nil {
+		sqlTx, err := c.DB.BeginTx(c.Context(), nil)
+		if err != nil {
+			return nil, err
+		}
+		tx.tx = sqlTx
+		return &tx, nil
+	}
+
+	if _, err := c.tx.ExecContext(c.Context(), "SAVEPOINT "+tx.savepoint()); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// Commit Commit the transaction, or release the savepoint of a nested one.
+func (c *Connection) Commit() error {
+	if c.tx == nil {
+		return ErrNoTransaction
+	}
+
+	if c.transactions == 1 {
+		return c.tx.Commit()
+	}
+
+	_, err := c.tx.ExecContext(c.Context(), "RELEASE SAVEPOINT "+c.savepoint())
+	return err
+}
+
+// Rollback Rollback the transaction, or roll back to the savepoint of a nested one.
+func (c *Connection) Rollback() error {
+	if c.tx == nil {
+		return ErrNoTransaction
+	}
+
+	if c.transactions == 1 {
+		return c.tx.Rollback()
+	}
+
+	_, err := c.tx.ExecContext(c.Context(), "ROLLBACK TO SAVEPOINT "+c.savepoint())
+	return err
+}
+
+// TransactionLevel Get the number of active transactions.
+func (c *Connection) TransactionLevel() int {
+	return c.transactions
+}
+
+func (c *Connection) savepoint() string {
+	return fmt.Sprintf("trans%d", c.transactions)
+}
@@ -0,0 +1,113 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	conn := newTestConnection(t)
+
+	err := conn.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "first"}); err != nil {
+			return err
+		}
+		_, _, err := tx.Table("users").Insert(map[string]interface{}{"name": "second"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := countUsers(t, conn); got != 2 {
+		t.Errorf("invalid user count: got:%d want:%d", got, 2)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	conn := newTestConnection(t)
+	failure := errors.New("failure")
+
+	err := conn.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "first"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("invalid transaction error: got:%v want:%v", err, failure)
+	}
+
+	if got := countUsers(t, conn); got != 0 {
+		t.Errorf("invalid user count: got:%d want:%d", got, 0)
+	}
+}
+
+func TestNestedTransactionUsesSavepoint(t *testing.T) {
+	conn := newTestConnection(t)
+
+	err := conn.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "outer"}); err != nil {
+			return err
+		}
+
+		nested := tx.Transaction(func(tx *Connection) error {
+			if tx.TransactionLevel() != 2 {
+				t.Errorf("invalid transaction level: got:%d want:%d", tx.TransactionLevel(), 2)
+			}
+			if err := tx.Create(&testUser{Name: "inner"}); err != nil {
+				return err
+			}
+			return errors.New("discard inner")
+		})
+		if nested == nil {
+			t.Error("expecting nested transaction error")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var users []testUser
+	if err := conn.Table("users").Get(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "outer" {
+		t.Errorf("invalid users: got:%+v", users)
+	}
+}
+
+func TestCommitWithoutTransaction(t *testing.T) {
+	conn := newTestConnection(t)
+
+	if err := conn.Commit(); err != ErrNoTransaction {
+		t.Errorf("invalid commit error: got:%v want:%v", err, ErrNoTransaction)
+	}
+}
+
+func TestLockForUpdateInTransaction(t *testing.T) {
+	conn := newTestConnection(t)
+	insertUsers(t, conn, "ana")
+
+	err := conn.Transaction(func(tx *Connection) error {
+		var user testUser
+		if err := tx.Table("users").Where("name", "ana").LockForUpdate().First(&user); err != nil {
+			return err
+		}
+		user.Name = "bob"
+		return tx.Save(&user)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var user testUser
+	if err := conn.Table("users").First(&user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "bob" {
+		t.Errorf("invalid user name: got:%s want:%s", user.Name, "bob")
+	}
+}
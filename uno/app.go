@@ -7,11 +7,12 @@ import (
 )
 
 type Application struct {
-	Env    string
-	Debug  bool
-	Logger *log.Logger
-	DB     *orm.Connection
-	route  *router.Route
+	Env       string
+	Debug     bool
+	Logger    *log.Logger
+	DB        *orm.Connection
+	Databases *orm.Manager
+	route     *router.Route
 }
 
 // Creates new Applications
@@ -2,22 +2,31 @@ package uno
 
 import (
 	"github.com/glugox/unogo/context"
+	"github.com/glugox/unogo/orm"
 	"github.com/glugox/unogo/router"
 )
 
 type Kernel struct {
 	Route *router.Route
+	DB    *orm.Connection
 }
 
 // NewKernel The default type
 func NewKernel(app *Application) Handler {
 	return &Kernel{
 		Route: app.GetRoute(),
+		DB:    app.DB,
 	}
 }
 
 // Process Process the request to a router and return the response.
+// The request gets a database connection bound to the context of the
+// incoming *http.Request, so its queries stop when the client goes away.
 func (h *Kernel) Process(request *context.Request, next Closure) interface{} {
+	if h.DB != nil {
+		request.DB = h.DB.WithContext(request.GetHttpRequest().Context())
+	}
+
 	rule, err := h.Route.Dispatch(request)
 
 	if err != nil {
@@ -37,14 +37,20 @@ func New() *Uno {
 	application := uno.NewApplication()
 	application.Logger = log.NewLogger("local", record.DEBUG)
 
-	// Configure DB connection
-	config := orm.Config{
-		Driver: "mysql",
-		Dsn:    "root:root@tcp(127.0.0.1:3306)/unogo?charset=utf8&parseTime=true",
+	// Configure DB connections
+	manager, err := orm.NewManager(*config.Database)
+	if err != nil {
+		application.Logger.Error("%s", err)
+	} else {
+		application.Databases = manager
+		if db, err := manager.Connection(); err == nil {
+			db.Listen(orm.LogQueries(application.Logger, time.Second))
+			application.DB = db
+		} else {
+			application.Logger.Error("%s", err)
+		}
 	}
-	db, _ := orm.Open(config)
 
-	application.DB = db
 	t := &Uno{
 		App: application,
 	}